@@ -1,15 +1,28 @@
 package main
 
 import (
+	"context"
+	"flag"
+	"log"
+
 	"github.com/Paperspace/terraform-provider-paperspace/pkg/provider"
-	"github.com/hashicorp/terraform-plugin-sdk/plugin"
-	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
 )
 
+// version is set at build time with -ldflags "-X main.version=...".
+var version = "dev"
+
 func main() {
-	plugin.Serve(&plugin.ServeOpts{
-		ProviderFunc: func() terraform.ResourceProvider {
-			return provider.Provider()
-		},
+	var debug bool
+
+	flag.BoolVar(&debug, "debug", false, "run the provider with support for debuggers like delve")
+	flag.Parse()
+
+	err := providerserver.Serve(context.Background(), provider.New(version), providerserver.ServeOpts{
+		Address: "registry.terraform.io/paperspace/paperspace",
+		Debug:   debug,
 	})
+	if err != nil {
+		log.Fatal(err.Error())
+	}
 }
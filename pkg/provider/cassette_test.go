@@ -0,0 +1,302 @@
+package provider
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+)
+
+// Cassettes are API interactions replayed by provider tests. The ones in
+// testdata/cassettes are synthetic: they are generated from the in-process
+// fake API, so they pin down the requests the provider sends but say nothing
+// about the payloads the real API returns. Set PAPERSPACE_RECORD=fake to
+// regenerate a test's cassette from the fake, or PAPERSPACE_RECORD=1 along
+// with PAPERSPACE_API_KEY (and optionally PAPERSPACE_API_HOST) to capture it
+// from the real API; otherwise tests replay the cassette without touching
+// the network.
+const (
+	cassetteDir       = "testdata/cassettes"
+	cassetteReplayURL = "https://api.paperspace.invalid"
+)
+
+// cassetteResponseHeaders are the only response headers recorded; the rest
+// (dates, request ids, cookies) change on every run.
+var cassetteResponseHeaders = []string{"Content-Type", "Retry-After"}
+
+type cassetteRequest struct {
+	Method string          `json:"method"`
+	URL    string          `json:"url"`
+	Body   json.RawMessage `json:"body,omitempty"`
+}
+
+type cassetteResponse struct {
+	StatusCode int               `json:"status_code"`
+	Headers    map[string]string `json:"headers,omitempty"`
+	Body       json.RawMessage   `json:"body,omitempty"`
+}
+
+type cassetteInteraction struct {
+	Request  cassetteRequest  `json:"request"`
+	Response cassetteResponse `json:"response"`
+}
+
+// cassette records interactions or replays them. Requests are matched on
+// method and URL in the order they were recorded, so repeated polls of the
+// same URL replay the sequence of states the API went through. Request
+// bodies must match exactly, which pins down what the provider sends.
+type cassette struct {
+	t         *testing.T
+	path      string
+	recording bool
+	// fake is the API recorded against with PAPERSPACE_RECORD=fake.
+	fake *fakeAPI
+
+	mu           sync.Mutex
+	interactions []cassetteInteraction
+	used         []bool
+}
+
+func newCassette(t *testing.T, name string) *cassette {
+	c := &cassette{
+		t:         t,
+		path:      filepath.Join(cassetteDir, name+".json"),
+		recording: os.Getenv("PAPERSPACE_RECORD") != "",
+	}
+
+	if os.Getenv("PAPERSPACE_RECORD") == "fake" {
+		c.fake = newFakeAPI(t)
+		t.Cleanup(c.save)
+		return c
+	}
+
+	if c.recording {
+		if os.Getenv("PAPERSPACE_API_KEY") == "" {
+			t.Fatal("PAPERSPACE_API_KEY must be set to record cassettes")
+		}
+		t.Cleanup(c.save)
+		return c
+	}
+
+	data, err := os.ReadFile(c.path)
+	if err != nil {
+		t.Fatalf("Error reading cassette: %s", err)
+	}
+	if err := json.Unmarshal(data, &c.interactions); err != nil {
+		t.Fatalf("Error parsing cassette %s: %s", c.path, err)
+	}
+	c.used = make([]bool, len(c.interactions))
+	t.Cleanup(c.checkAllUsed)
+
+	return c
+}
+
+// providerConfig returns a provider block for the cassette's mode.
+func (c *cassette) providerConfig() string {
+	if c.fake != nil {
+		return c.fake.providerConfig()
+	}
+
+	if c.recording {
+		return fmt.Sprintf(`
+provider "paperspace" {
+  region = %q
+}
+`, fakeRegion)
+	}
+
+	return fmt.Sprintf(`
+provider "paperspace" {
+  api_key             = "replay"
+  api_host            = %q
+  region              = %q
+  requests_per_second = 0
+}
+`, cassetteReplayURL, fakeRegion)
+}
+
+// providers returns a provider whose shared HTTP client goes through the
+// cassette, which covers both the internal client and the paperspace-go
+// backend.
+func (c *cassette) providers() map[string]func() (tfprotov6.ProviderServer, error) {
+	p := &paperspaceProvider{
+		version: "test",
+		wrapTransport: func(next http.RoundTripper) http.RoundTripper {
+			return &cassetteTransport{cassette: c, next: next}
+		},
+	}
+
+	return map[string]func() (tfprotov6.ProviderServer, error){
+		"paperspace": providerserver.NewProtocol6WithError(p),
+	}
+}
+
+type cassetteTransport struct {
+	cassette *cassette
+	next     http.RoundTripper
+}
+
+func (t *cassetteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	request := cassetteRequest{
+		Method: req.Method,
+		URL:    req.URL.RequestURI(),
+		Body:   scrubCassetteBody(body),
+	}
+
+	if !t.cassette.recording {
+		if req.Header.Get("x-api-key") == "" {
+			return nil, fmt.Errorf("cassette: %s %s sent without an API key", req.Method, request.URL)
+		}
+		return t.cassette.replay(req, request)
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	response := cassetteResponse{
+		StatusCode: resp.StatusCode,
+		Headers:    map[string]string{},
+		Body:       scrubCassetteBody(respBody),
+	}
+	for _, k := range cassetteResponseHeaders {
+		if v := resp.Header.Get(k); v != "" {
+			response.Headers[k] = v
+		}
+	}
+
+	t.cassette.record(cassetteInteraction{Request: request, Response: response})
+
+	return resp, nil
+}
+
+func (c *cassette) record(interaction cassetteInteraction) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.interactions = append(c.interactions, interaction)
+}
+
+func (c *cassette) replay(req *http.Request, request cassetteRequest) (*http.Response, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for i, interaction := range c.interactions {
+		if c.used[i] || interaction.Request.Method != request.Method || interaction.Request.URL != request.URL {
+			continue
+		}
+
+		if !equalCassetteBodies(interaction.Request.Body, request.Body) {
+			err := fmt.Errorf("cassette %s: %s %s body mismatch:\nrecorded: %s\n    sent: %s", c.path, request.Method, request.URL, interaction.Request.Body, request.Body)
+			c.t.Error(err)
+			return nil, err
+		}
+		c.used[i] = true
+
+		resp := &http.Response{
+			StatusCode:    interaction.Response.StatusCode,
+			Status:        fmt.Sprintf("%d %s", interaction.Response.StatusCode, http.StatusText(interaction.Response.StatusCode)),
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        http.Header{},
+			Body:          io.NopCloser(bytes.NewReader(interaction.Response.Body)),
+			ContentLength: int64(len(interaction.Response.Body)),
+			Request:       req,
+		}
+		for k, v := range interaction.Response.Headers {
+			resp.Header.Set(k, v)
+		}
+
+		return resp, nil
+	}
+
+	err := fmt.Errorf("cassette %s: no recorded interaction left for %s %s", c.path, request.Method, request.URL)
+	c.t.Error(err)
+	return nil, err
+}
+
+func (c *cassette) checkAllUsed() {
+	for i, used := range c.used {
+		if !used {
+			request := c.interactions[i].Request
+			c.t.Errorf("cassette %s: recorded interaction %d (%s %s) was never replayed", c.path, i, request.Method, request.URL)
+		}
+	}
+}
+
+func (c *cassette) save() {
+	if c.t.Failed() {
+		c.t.Logf("not saving cassette %s for a failed test", c.path)
+		return
+	}
+
+	data, err := json.MarshalIndent(c.interactions, "", "  ")
+	if err != nil {
+		c.t.Errorf("Error encoding cassette: %s", err)
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0755); err != nil {
+		c.t.Errorf("Error writing cassette: %s", err)
+		return
+	}
+	if err := os.WriteFile(c.path, append(data, '\n'), 0644); err != nil {
+		c.t.Errorf("Error writing cassette: %s", err)
+	}
+}
+
+// scrubCassetteBody redacts secrets from a JSON body before it is stored or
+// compared. Bodies that are not JSON are kept as a JSON string.
+func scrubCassetteBody(body []byte) json.RawMessage {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return nil
+	}
+
+	if !json.Valid(body) {
+		quoted, _ := json.Marshal(string(body))
+		return quoted
+	}
+
+	return json.RawMessage(redactJSON(body))
+}
+
+func equalCassetteBodies(recorded, sent json.RawMessage) bool {
+	if len(recorded) == 0 || len(sent) == 0 {
+		return len(recorded) == len(sent)
+	}
+
+	var a, b interface{}
+	if json.Unmarshal(recorded, &a) != nil || json.Unmarshal(sent, &b) != nil {
+		return bytes.Equal(recorded, sent)
+	}
+
+	return reflect.DeepEqual(a, b)
+}
@@ -2,6 +2,7 @@ package provider
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -10,22 +11,13 @@ import (
 	"net/url"
 	"reflect"
 	"strconv"
-	"strings"
 	"time"
 
 	"github.com/davecgh/go-spew/spew"
-	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
-var MachineNotFoundError = "Error on GetMachine: machine not found"
-var MachineDeleteNotFoundError = "Error on DeleteMachine: machine not found"
-
-var RegionMap = map[string]int{
-	"East Coast (NY2)": 1,
-	"West Coast (CA1)": 2,
-	"Europe (AMS1)":    3,
-}
-
 type JobStorage struct {
 	Handle string           `json:"handle"`
 	TeamID int              `json:"teamId"`
@@ -60,50 +52,216 @@ type CreateTeamNamedNetworkParams struct {
 	RegionId int    `json:"regionId"`
 }
 
-type MapIf map[string]interface{}
+// FlexString decodes JSON strings and numbers alike, for fields the API
+// returns inconsistently (e.g. ram and storage sizes).
+type FlexString string
+
+func (f *FlexString) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*f = FlexString(s)
+		return nil
+	}
+
+	var n json.Number
+	if err := json.Unmarshal(data, &n); err != nil {
+		return fmt.Errorf("FlexString: cannot decode %s", data)
+	}
+	*f = FlexString(n.String())
 
-func (m *MapIf) Append(d *schema.ResourceData, k string) {
-	v := d.Get(k)
-	(*m)[k] = v
+	return nil
 }
 
-func (m *MapIf) AppendAs(d *schema.ResourceData, k, nk string) {
-	v := d.Get(k)
-	(*m)[nk] = v
+type Machine struct {
+	ID                     string     `json:"id"`
+	Name                   string     `json:"name"`
+	OS                     string     `json:"os"`
+	RAM                    FlexString `json:"ram"`
+	CPUs                   int        `json:"cpus"`
+	GPU                    string     `json:"gpu"`
+	StorageTotal           FlexString `json:"storageTotal"`
+	StorageUsed            FlexString `json:"storageUsed"`
+	UsageRate              string     `json:"usageRate"`
+	ShutdownTimeoutInHours *int       `json:"shutdownTimeoutInHours"`
+	ShutdownTimeoutForces  bool       `json:"shutdownTimeoutForces"`
+	PerformAutoSnapshot    bool       `json:"performAutoSnapshot"`
+	AutoSnapshotFrequency  FlexString `json:"autoSnapshotFrequency"`
+	AutoSnapshotSaveCount  int        `json:"autoSnapshotSaveCount"`
+	AgentType              string     `json:"agentType"`
+	DtCreated              string     `json:"dtCreated"`
+	State                  string     `json:"state"`
+	NetworkID              string     `json:"networkId"`
+	PrivateIpAddress       string     `json:"privateIpAddress"`
+	PublicIpAddress        string     `json:"publicIpAddress"`
+	Region                 string     `json:"region"`
+	UserID                 string     `json:"userId"`
+	TeamID                 string     `json:"teamId"`
+	ScriptID               string     `json:"scriptId"`
+	DtLastRun              string     `json:"dtLastRun"`
+	IsManaged              bool       `json:"isManaged"`
 }
 
-func (m *MapIf) AppendV(d *schema.ResourceData, k, v string) {
-	(*m)[k] = v
+type MachineCreateParams struct {
+	Region                 string `json:"region"`
+	MachineType            string `json:"machineType"`
+	Size                   int    `json:"size"`
+	BillingType            string `json:"billingType"`
+	Name                   string `json:"machineName"`
+	TemplateID             string `json:"templateId"`
+	AssignPublicIP         bool   `json:"assignPublicIp,omitempty"`
+	UserID                 string `json:"userId,omitempty"`
+	TeamID                 string `json:"teamId,omitempty"`
+	ScriptID               string `json:"scriptId,omitempty"`
+	NetworkID              string `json:"networkId,omitempty"`
+	ShutdownTimeoutInHours int    `json:"shutdownTimeoutInHours,omitempty"`
+	IsManaged              bool   `json:"isManaged,omitempty"`
+	PerformAutoSnapshot    bool   `json:"performAutoSnapshot,omitempty"`
+	AutoSnapshotFrequency  string `json:"autoSnapshotFrequency,omitempty"`
+	AutoSnapshotSaveCount  int    `json:"autoSnapshotSaveCount,omitempty"`
+	Email                  string `json:"email,omitempty"`
+	Password               string `json:"password,omitempty"`
+	FirstName              string `json:"firstName,omitempty"`
+	LastName               string `json:"lastName,omitempty"`
+	NotificationEmail      string `json:"notificationEmail,omitempty"`
+
+	// LiveForever sends an explicit null shutdownTimeoutInHours so the
+	// machine never shuts down automatically.
+	LiveForever bool `json:"-"`
 }
 
-func (m *MapIf) AppendIfSet(d *schema.ResourceData, k string) {
-	v := d.Get(k)
-	if reflect.ValueOf(v).Interface() != reflect.Zero(reflect.TypeOf(v)).Interface() {
-		(*m)[k] = v
+func (p MachineCreateParams) MarshalJSON() ([]byte, error) {
+	type params MachineCreateParams
+	if !p.LiveForever {
+		return json.Marshal(params(p))
 	}
+
+	return json.Marshal(struct {
+		params
+		ShutdownTimeoutInHours *int `json:"shutdownTimeoutInHours"`
+	}{params: params(p)})
 }
 
-func (m *MapIf) AppendAsIfSet(d *schema.ResourceData, k, nk string) {
-	v := d.Get(k)
-	if reflect.ValueOf(v).Interface() != reflect.Zero(reflect.TypeOf(v)).Interface() {
-		(*m)[nk] = v
+// MachineUpdateParams only carries the fields being changed; nil fields are
+// left untouched by the API.
+type MachineUpdateParams struct {
+	Name                   *string `json:"machineName,omitempty"`
+	ShutdownTimeoutInHours *int    `json:"shutdownTimeoutInHours,omitempty"`
+	PerformAutoSnapshot    *bool   `json:"performAutoSnapshot,omitempty"`
+	AutoSnapshotFrequency  *string `json:"autoSnapshotFrequency,omitempty"`
+	AutoSnapshotSaveCount  *int    `json:"autoSnapshotSaveCount,omitempty"`
+
+	// LiveForever sends an explicit null shutdownTimeoutInHours so the
+	// machine never shuts down automatically.
+	LiveForever bool `json:"-"`
+}
+
+func (p MachineUpdateParams) MarshalJSON() ([]byte, error) {
+	type params MachineUpdateParams
+	if !p.LiveForever {
+		return json.Marshal(params(p))
 	}
+
+	return json.Marshal(struct {
+		params
+		ShutdownTimeoutInHours *int `json:"shutdownTimeoutInHours"`
+	}{params: params(p)})
+}
+
+type Script struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	OwnerType   string `json:"ownerType"`
+	OwnerID     string `json:"ownerId"`
+	DtCreated   string `json:"dtCreated"`
+	IsEnabled   bool   `json:"isEnabled"`
+	RunOnce     bool   `json:"runOnce"`
+}
+
+// User, Template and PrivateNetwork are the objects listed by the lookup
+// endpoints. Empty fields are left out when they are handed to a lookup, so
+// that they read as null like fields the API leaves out.
+type User struct {
+	ID        string     `json:"id,omitempty"`
+	Email     string     `json:"email,omitempty"`
+	Firstname string     `json:"firstname,omitempty"`
+	Lastname  string     `json:"lastname,omitempty"`
+	DtCreated string     `json:"dtCreated,omitempty"`
+	TeamID    FlexString `json:"teamId,omitempty"`
+}
+
+type Template struct {
+	ID        string     `json:"id,omitempty"`
+	Name      string     `json:"name,omitempty"`
+	Label     string     `json:"label,omitempty"`
+	OS        string     `json:"os,omitempty"`
+	DtCreated string     `json:"dtCreated,omitempty"`
+	TeamID    FlexString `json:"teamId,omitempty"`
+	UserID    string     `json:"userId,omitempty"`
+	Region    string     `json:"region,omitempty"`
+}
+
+type PrivateNetwork struct {
+	ID        string     `json:"id,omitempty"`
+	Name      string     `json:"name,omitempty"`
+	Region    string     `json:"region,omitempty"`
+	DtCreated string     `json:"dtCreated,omitempty"`
+	Network   string     `json:"network,omitempty"`
+	Netmask   string     `json:"netmask,omitempty"`
+	TeamID    FlexString `json:"teamId,omitempty"`
+}
+
+type ScriptCreateParams struct {
+	Name        string `json:"scriptName"`
+	Text        string `json:"scriptText"`
+	Description string `json:"scriptDescription,omitempty"`
+	IsEnabled   *bool  `json:"isEnabled,omitempty"`
+	RunOnce     *bool  `json:"runOnce,omitempty"`
 }
 
-func SetResDataFrom(d *schema.ResourceData, m map[string]interface{}, dn, n string) {
-	v, ok := m[n]
-	//log.Printf("%v %v\n", n, v)
-	if ok {
-		d.Set(dn, v)
+// MapIf builds request bodies from attribute values.
+type MapIf map[string]interface{}
+
+// Append adds the Go value of v under k. Null and unknown values are added as
+// their zero value.
+func (m MapIf) Append(k string, v attr.Value) {
+	m[k] = attrGoValue(v)
+}
+
+// AppendIfSet adds the Go value of v under k unless it is null, unknown or
+// the zero value.
+func (m MapIf) AppendIfSet(k string, v attr.Value) {
+	if v.IsNull() || v.IsUnknown() {
+		return
+	}
+
+	value := attrGoValue(v)
+	if value != nil && value != reflect.Zero(reflect.TypeOf(value)).Interface() {
+		m[k] = value
 	}
 }
 
-func SetResData(d *schema.ResourceData, m map[string]interface{}, n string) {
-	SetResDataFrom(d, m, n, n)
+func attrGoValue(v attr.Value) interface{} {
+	switch value := v.(type) {
+	case types.String:
+		return value.ValueString()
+	case types.Int64:
+		return value.ValueInt64()
+	case types.Bool:
+		return value.ValueBool()
+	case types.Float64:
+		return value.ValueFloat64()
+	}
+
+	return nil
 }
 
 func logHttpRequestConstruction(operationType string, url string, data *bytes.Buffer) {
-	log.Printf("Constructing %s request to url: %s, data: %v", operationType, url, data)
+	log.Printf("Constructing %s request to url: %s, data: %s", operationType, url, redactJSON(data.Bytes()))
 }
 
 // LogHttpResponse logs http response fields
@@ -111,15 +269,27 @@ func LogHttpResponse(reqDesc string, reqURL *url.URL, resp *http.Response, body
 	log.Printf("Request: %v", reqDesc)
 	log.Printf("Request URL: %v", reqURL)
 	log.Printf("Response Status: %v", resp.Status)
-	log.Printf("Response: %v", resp)
-	log.Printf("Response Body: %s", spew.Sdump(body))
+	log.Printf("Response Headers: %v", redactHeaders(resp.Header))
+	log.Printf("Response Body: %s", spew.Sdump(redactValue(body)))
 	log.Printf("Error: %v", err)
 }
 
 type ClientConfig struct {
-	APIKey  string
-	APIHost string
-	Region  string
+	APIKey         string
+	APIHost        string
+	Region         string
+	MaxRetries     int
+	RetryMaxWait   time.Duration
+	RequestTimeout time.Duration
+	RateLimiter    *rateLimiter
+	Transport      TransportConfig
+
+	// MachineTypes caches the machine type catalog used to validate plans.
+	MachineTypes *machineTypeCatalog
+
+	// HTTPClient is built once by providerConfigure and shared by every
+	// client so connections are pooled across resources and polling loops.
+	HTTPClient *http.Client
 }
 
 type PaperspaceClient struct {
@@ -129,26 +299,61 @@ type PaperspaceClient struct {
 	HttpClient *http.Client
 }
 
-func (c *ClientConfig) Client() (paperspaceClient PaperspaceClient) {
-	timeout := 30 * time.Second
-	client := &http.Client{
-		Timeout: timeout,
+// NewHTTPClient builds the http.Client shared by the internal client and the
+// paperspace-go backend. Timeouts are applied per attempt by the retry
+// transport rather than on the client as a whole.
+func (c *ClientConfig) NewHTTPClient() (*http.Client, error) {
+	httpTransport, err := newHTTPTransport(c.Transport)
+	if err != nil {
+		return nil, err
+	}
+
+	var transport http.RoundTripper = httpTransport
+	if c.RateLimiter != nil {
+		transport = &rateLimitTransport{next: transport, limiter: c.RateLimiter}
+	}
+
+	requestTimeout := c.RequestTimeout
+	if requestTimeout <= 0 {
+		requestTimeout = defaultAttemptTimeout
+	}
+
+	return &http.Client{
+		Transport: newRetryTransport(transport, c.MaxRetries, c.RetryMaxWait, requestTimeout),
+	}, nil
+}
+
+func (c *ClientConfig) httpClient() *http.Client {
+	if c.HTTPClient != nil {
+		return c.HTTPClient
+	}
+
+	// only reached for configs not built by providerConfigure
+	client, err := c.NewHTTPClient()
+	if err != nil {
+		log.Printf("[WARN] paperspace client falling back to default transport: %s", err)
+		return &http.Client{Timeout: defaultAttemptTimeout}
 	}
+	c.HTTPClient = client
 
+	return client
+}
+
+func (c *ClientConfig) Client() (paperspaceClient PaperspaceClient) {
 	paperspaceClient = PaperspaceClient{
 		APIKey:     c.APIKey,
 		APIHost:    c.APIHost,
 		Region:     c.Region,
-		HttpClient: client,
+		HttpClient: c.httpClient(),
 	}
 
-	log.Printf("[DEBUG] Paperspace client config %v", paperspaceClient)
+	log.Printf("[DEBUG] Paperspace client config api_host: %v, region: %v", paperspaceClient.APIHost, paperspaceClient.Region)
 
 	return paperspaceClient
 }
 
-func (paperspaceClient *PaperspaceClient) NewHttpRequest(method, url string, buf io.Reader) (*http.Request, error) {
-	req, err := http.NewRequest(method, url, buf)
+func (paperspaceClient *PaperspaceClient) NewHttpRequest(ctx context.Context, method, url string, buf io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, buf)
 	if err != nil {
 		return nil, err
 	}
@@ -162,158 +367,173 @@ func (paperspaceClient *PaperspaceClient) NewHttpRequest(method, url string, buf
 	return req, nil
 }
 
-func (paperspaceClient *PaperspaceClient) RequestInterface(method string, url string, params, result interface{}) (res *http.Response, err error) {
+// do sends a request and returns the raw response body. Non-2xx responses
+// are returned as an *APIError.
+func (paperspaceClient *PaperspaceClient) do(ctx context.Context, method string, url string, data []byte) (resp *http.Response, body []byte, err error) {
+	logHttpRequestConstruction(method, url, bytes.NewBuffer(data))
+
+	req, err := paperspaceClient.NewHttpRequest(ctx, method, url, bytes.NewReader(data))
+	if err != nil {
+		return nil, nil, fmt.Errorf("Error constructing request: %s", err)
+	}
+
+	resp, err = paperspaceClient.HttpClient.Do(req)
+	if err != nil {
+		return resp, nil, fmt.Errorf("Error completing request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err = io.ReadAll(resp.Body)
+	if err != nil {
+		return resp, nil, fmt.Errorf("Error reading response body: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		apiError := newAPIError(method, url, resp.StatusCode, body)
+		LogHttpResponse("", req.URL, resp, apiError.Body, apiError)
+		return resp, body, apiError
+	}
+
+	return resp, body, nil
+}
+
+func (paperspaceClient *PaperspaceClient) RequestInterface(ctx context.Context, method string, url string, params, result interface{}) (res *http.Response, err error) {
 	var data []byte
-	body := bytes.NewReader(make([]byte, 0))
 
 	if params != nil {
 		data, err = json.Marshal(params)
 		if err != nil {
 			return res, err
 		}
-
-		body = bytes.NewReader(data)
 	}
 
-	buf := bytes.NewBuffer(data)
-	logHttpRequestConstruction(method, url, buf)
-
-	req, err := paperspaceClient.NewHttpRequest(method, url, body)
+	res, body, err := paperspaceClient.do(ctx, method, url, data)
 	if err != nil {
-		return nil, err
+		return res, err
 	}
 
-	resp, err := paperspaceClient.HttpClient.Do(req)
-	if err != nil {
-		return resp, err
+	// some endpoints reply with an empty body on success
+	if len(bytes.TrimSpace(body)) == 0 || result == nil {
+		LogHttpResponse("", res.Request.URL, res, nil, nil)
+		return res, nil
 	}
-	defer resp.Body.Close()
 
-	err = json.NewDecoder(resp.Body).Decode(&result)
+	err = json.Unmarshal(body, &result)
 	if err != nil {
-		return resp, err
+		return res, fmt.Errorf("Error decoding response body: %s", err)
 	}
 
-	LogHttpResponse("", req.URL, resp, result, err)
-	return resp, nil
+	LogHttpResponse("", res.Request.URL, res, result, err)
+	return res, nil
 }
 
-func (paperspaceClient *PaperspaceClient) Request(method string, url string, data []byte) (body map[string]interface{}, statusCode int, err error) {
-	buf := bytes.NewBuffer(data)
-
-	logHttpRequestConstruction(method, url, buf)
-
-	req, err := paperspaceClient.NewHttpRequest(method, url, buf)
-	if err != nil {
-		return nil, statusCode, fmt.Errorf("Error constructing request: %s", err)
+func (paperspaceClient *PaperspaceClient) Request(ctx context.Context, method string, url string, data []byte) (body map[string]interface{}, statusCode int, err error) {
+	res, respBody, err := paperspaceClient.do(ctx, method, url, data)
+	if res != nil {
+		statusCode = res.StatusCode
 	}
-
-	resp, err := paperspaceClient.HttpClient.Do(req)
 	if err != nil {
-		if resp != nil {
-			statusCode = resp.StatusCode
-		}
-		return nil, statusCode, fmt.Errorf("Error completing request: %s", err)
+		return nil, statusCode, err
 	}
-	defer resp.Body.Close()
 
-	err = json.NewDecoder(resp.Body).Decode(&body)
-	if err != nil {
-		if resp != nil {
-			statusCode = resp.StatusCode
+	// some endpoints reply with an empty body on success
+	if len(bytes.TrimSpace(respBody)) > 0 {
+		if err := json.Unmarshal(respBody, &body); err != nil {
+			return nil, statusCode, fmt.Errorf("Error decoding response body: %s", err)
 		}
-		return nil, statusCode, fmt.Errorf("Error decoding response body: %s", err)
 	}
 
-	LogHttpResponse("", req.URL, resp, body, err)
+	LogHttpResponse("", res.Request.URL, res, body, nil)
 
-	return body, resp.StatusCode, nil
+	return body, statusCode, nil
 }
 
-func (paperspaceClient *PaperspaceClient) GetMachine(id string) (body map[string]interface{}, err error) {
+func (paperspaceClient *PaperspaceClient) GetMachine(ctx context.Context, id string) (machine Machine, err error) {
 	url := fmt.Sprintf("%s/machines/getMachinePublic?machineId=%s", paperspaceClient.APIHost, id)
-	body, statusCode, err := paperspaceClient.Request("GET", url, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	if statusCode != 404 && statusCode != 200 {
-		return nil, fmt.Errorf("Error on GetMachine response: statusCode: %d", statusCode)
+	if _, err := paperspaceClient.RequestInterface(ctx, "GET", url, nil, &machine); err != nil {
+		return machine, err
 	}
 
-	nextID, _ := body["id"].(string)
-	if statusCode == 404 || nextID == "" {
-		return nil, fmt.Errorf(MachineNotFoundError)
+	if machine.ID == "" {
+		return machine, newNotFoundError("GET", url, "machine not found")
 	}
 
-	return body, nil
+	return machine, nil
 }
 
-func (paperspaceClient *PaperspaceClient) CreateMachine(data []byte) (id string, err error) {
+func (paperspaceClient *PaperspaceClient) CreateMachine(ctx context.Context, params MachineCreateParams) (machine Machine, err error) {
 	url := fmt.Sprintf("%s/machines/createSingleMachinePublic", paperspaceClient.APIHost)
-	body, statusCode, err := paperspaceClient.Request("POST", url, data)
-	if err != nil {
-		return "", err
+	if _, err := paperspaceClient.RequestInterface(ctx, "POST", url, params, &machine); err != nil {
+		return machine, fmt.Errorf("Error on CreateMachine: %w", err)
 	}
 
-	if statusCode != 200 {
-		jsonBody, err := json.Marshal(body)
-		if err != nil {
-			return "", fmt.Errorf("Error unmarshaling response body: %v", err)
-		}
-
-		return "", fmt.Errorf("Error on CreateMachine: Status Code %d, Response Body: %s", statusCode, jsonBody)
+	if machine.ID == "" {
+		return machine, fmt.Errorf("Error on CreateMachine: id not found")
 	}
 
-	id, _ = body["id"].(string)
+	return machine, nil
+}
 
-	if id == "" {
-		return "", fmt.Errorf("Error on CreateMachine: id not found")
-	}
+func (paperspaceClient *PaperspaceClient) UpdateMachine(ctx context.Context, id string, params MachineUpdateParams) (err error) {
+	url := fmt.Sprintf("%s/machines/%s/updateMachine", paperspaceClient.APIHost, id)
+	_, err = paperspaceClient.RequestInterface(ctx, "POST", url, params, nil)
 
-	return id, nil
+	return err
 }
 
-func (paperspaceClient *PaperspaceClient) DeleteMachine(id string) (err error) {
-	url := fmt.Sprintf("%s/machines/%s/destroyMachine", paperspaceClient.APIHost, id)
-	_, statusCode, err := paperspaceClient.Request("POST", url, nil)
-	// /destroyMachine returns the string "EOF" if it was successful, which can't be JSON-decoded
-	if err != nil && !strings.Contains(err.Error(), "EOF") {
-		return err
-	}
+// SetMachineScript sets the startup script of an existing machine, or clears
+// it when scriptID is empty. scriptId is not among the documented
+// updateMachine parameters, so only paperspace_machine_script_attachment
+// relies on it.
+func (paperspaceClient *PaperspaceClient) SetMachineScript(ctx context.Context, id string, scriptID string) (err error) {
+	url := fmt.Sprintf("%s/machines/%s/updateMachine", paperspaceClient.APIHost, id)
+	_, err = paperspaceClient.RequestInterface(ctx, "POST", url, map[string]string{"scriptId": scriptID}, nil)
 
-	if statusCode != 204 {
-		return fmt.Errorf("Error deleting machine")
-	}
-	if statusCode != 404 {
-		return fmt.Errorf(MachineDeleteNotFoundError)
-	}
+	return err
+}
 
-	return nil
+func (paperspaceClient *PaperspaceClient) StartMachine(ctx context.Context, id string) (err error) {
+	return paperspaceClient.machineAction(ctx, id, "start")
+}
+
+func (paperspaceClient *PaperspaceClient) StopMachine(ctx context.Context, id string) (err error) {
+	return paperspaceClient.machineAction(ctx, id, "stop")
+}
+
+func (paperspaceClient *PaperspaceClient) machineAction(ctx context.Context, id, action string) (err error) {
+	url := fmt.Sprintf("%s/machines/%s/%s", paperspaceClient.APIHost, id, action)
+	_, err = paperspaceClient.RequestInterface(ctx, "POST", url, nil, nil)
+
+	return err
+}
+
+func (paperspaceClient *PaperspaceClient) DeleteMachine(ctx context.Context, id string) (err error) {
+	url := fmt.Sprintf("%s/machines/%s/destroyMachine", paperspaceClient.APIHost, id)
+	_, err = paperspaceClient.RequestInterface(ctx, "POST", url, nil, nil)
+
+	return err
 }
 
-func (paperspaceClient *PaperspaceClient) CreateTeamNamedNetwork(teamID int, createNamedNetworkParams CreateTeamNamedNetworkParams) error {
+func (paperspaceClient *PaperspaceClient) CreateTeamNamedNetwork(ctx context.Context, teamID int, createNamedNetworkParams CreateTeamNamedNetworkParams) error {
 	var network Network
 	url := fmt.Sprintf("%s/teams/%d/createPrivateNetwork", paperspaceClient.APIHost, teamID)
 
-	_, err := paperspaceClient.RequestInterface("POST", url, createNamedNetworkParams, &network)
-	if err != nil && strings.Contains(err.Error(), "EOF") {
-		return nil
-	}
+	_, err := paperspaceClient.RequestInterface(ctx, "POST", url, createNamedNetworkParams, &network)
+
 	return err
 }
 
-func (paperspaceClient *PaperspaceClient) GetTeamNamedNetworks(teamID int) ([]NamedNetwork, error) {
+func (paperspaceClient *PaperspaceClient) GetTeamNamedNetworks(ctx context.Context, teamID int) ([]NamedNetwork, error) {
 	var namedNetworks []NamedNetwork
 	url := fmt.Sprintf("%s/teams/%d/getNetworks", paperspaceClient.APIHost, teamID)
 
-	_, err := paperspaceClient.RequestInterface("GET", url, nil, &namedNetworks)
+	_, err := paperspaceClient.RequestInterface(ctx, "GET", url, nil, &namedNetworks)
 
 	return namedNetworks, err
 }
 
-func (paperspaceClient *PaperspaceClient) GetTeamNamedNetwork(teamID int, name string) (*NamedNetwork, error) {
-	namedNetworks, err := paperspaceClient.GetTeamNamedNetworks(teamID)
+func (paperspaceClient *PaperspaceClient) GetTeamNamedNetwork(ctx context.Context, teamID int, name string) (*NamedNetwork, error) {
+	namedNetworks, err := paperspaceClient.GetTeamNamedNetworks(ctx, teamID)
 	if err != nil {
 		return nil, err
 	}
@@ -327,8 +547,8 @@ func (paperspaceClient *PaperspaceClient) GetTeamNamedNetwork(teamID int, name s
 	return nil, fmt.Errorf("Error getting private network by name: %s", name)
 }
 
-func (paperspaceClient *PaperspaceClient) GetTeamNamedNetworkById(teamID int, id string) (*NamedNetwork, error) {
-	namedNetworks, err := paperspaceClient.GetTeamNamedNetworks(teamID)
+func (paperspaceClient *PaperspaceClient) GetTeamNamedNetworkById(ctx context.Context, teamID int, id string) (*NamedNetwork, error) {
+	namedNetworks, err := paperspaceClient.GetTeamNamedNetworks(ctx, teamID)
 	if err != nil {
 		return nil, err
 	}
@@ -339,15 +559,15 @@ func (paperspaceClient *PaperspaceClient) GetTeamNamedNetworkById(teamID int, id
 		}
 	}
 
-	return nil, fmt.Errorf("Error getting private network by id: %s", id)
+	return nil, newNotFoundError("GET", fmt.Sprintf("%s/teams/%d/getNetworks", paperspaceClient.APIHost, teamID), fmt.Sprintf("private network %s not found", id))
 }
 
-func (paperspaceClient *PaperspaceClient) GetJobStorageByRegion(teamID int, region string) (JobStorage, error) {
+func (paperspaceClient *PaperspaceClient) GetJobStorageByRegion(ctx context.Context, teamID int, region string) (JobStorage, error) {
 	var jobStorage JobStorage
 	var jobStorages []JobStorage
 	url := fmt.Sprintf("%s/accounts/team/%d/getJobStorage", paperspaceClient.APIHost, teamID)
 
-	_, err := paperspaceClient.RequestInterface("GET", url, nil, &jobStorages)
+	_, err := paperspaceClient.RequestInterface(ctx, "GET", url, nil, &jobStorages)
 	if err != nil {
 		return jobStorage, err
 	}
@@ -360,3 +580,74 @@ func (paperspaceClient *PaperspaceClient) GetJobStorageByRegion(teamID int, regi
 
 	return jobStorage, nil
 }
+
+func (paperspaceClient *PaperspaceClient) CreateScript(ctx context.Context, params ScriptCreateParams) (script Script, err error) {
+	url := fmt.Sprintf("%s/scripts/createScript", paperspaceClient.APIHost)
+	if _, err := paperspaceClient.RequestInterface(ctx, "POST", url, params, &script); err != nil {
+		return script, fmt.Errorf("Error on CreateScript: %w", err)
+	}
+
+	if script.ID == "" {
+		return script, fmt.Errorf("Error on CreateScript: id not found")
+	}
+
+	return script, nil
+}
+
+func (paperspaceClient *PaperspaceClient) GetScript(ctx context.Context, id string) (script Script, err error) {
+	url := fmt.Sprintf("%s/scripts/getScript?scriptId=%s", paperspaceClient.APIHost, id)
+	if _, err := paperspaceClient.RequestInterface(ctx, "GET", url, nil, &script); err != nil {
+		return script, err
+	}
+
+	if script.ID == "" {
+		return script, newNotFoundError("GET", url, "script not found")
+	}
+
+	return script, nil
+}
+
+func (paperspaceClient *PaperspaceClient) GetScripts(ctx context.Context, query url.Values) (scripts []Script, err error) {
+	url := fmt.Sprintf("%s/scripts/getScripts?%s", paperspaceClient.APIHost, query.Encode())
+	_, err = paperspaceClient.RequestInterface(ctx, "GET", url, nil, &scripts)
+
+	return scripts, err
+}
+
+// GetScriptText returns the text of a script exactly as the API holds it.
+func (paperspaceClient *PaperspaceClient) GetScriptText(ctx context.Context, id string) (text string, err error) {
+	url := fmt.Sprintf("%s/scripts/getScriptText?scriptId=%s", paperspaceClient.APIHost, id)
+
+	// the text is served as a JSON string
+	_, err = paperspaceClient.RequestInterface(ctx, "GET", url, nil, &text)
+
+	return text, err
+}
+
+func (paperspaceClient *PaperspaceClient) DeleteScript(ctx context.Context, id string) (err error) {
+	url := fmt.Sprintf("%s/scripts/%s/destroy", paperspaceClient.APIHost, id)
+	_, err = paperspaceClient.RequestInterface(ctx, "POST", url, nil, nil)
+
+	return err
+}
+
+func (paperspaceClient *PaperspaceClient) GetUsers(ctx context.Context, query url.Values) (users []User, err error) {
+	url := fmt.Sprintf("%s/users/getUsers?%s", paperspaceClient.APIHost, query.Encode())
+	_, err = paperspaceClient.RequestInterface(ctx, "GET", url, nil, &users)
+
+	return users, err
+}
+
+func (paperspaceClient *PaperspaceClient) GetTemplates(ctx context.Context, query url.Values) (templates []Template, err error) {
+	url := fmt.Sprintf("%s/templates/getTemplates?%s", paperspaceClient.APIHost, query.Encode())
+	_, err = paperspaceClient.RequestInterface(ctx, "GET", url, nil, &templates)
+
+	return templates, err
+}
+
+func (paperspaceClient *PaperspaceClient) GetNetworks(ctx context.Context, query url.Values) (networks []PrivateNetwork, err error) {
+	url := fmt.Sprintf("%s/networks/getNetworks?%s", paperspaceClient.APIHost, query.Encode())
+	_, err = paperspaceClient.RequestInterface(ctx, "GET", url, nil, &networks)
+
+	return networks, err
+}
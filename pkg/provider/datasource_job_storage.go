@@ -1,62 +1,96 @@
 package provider
 
 import (
-	"errors"
+	"context"
 	"fmt"
 
-	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
-func dataSourceJobStorageRead(d *schema.ResourceData, m interface{}) error {
-	paperspaceClient := newInternalPaperspaceClient(m)
-	region := paperspaceClient.Region
+type jobStorageDataSource struct {
+	meta interface{}
+}
 
-	teamID, ok := d.Get("team_id").(int)
-	if !ok {
-		return fmt.Errorf("team_id is not a int")
-	}
-	regionData, ok := d.Get("region").(string)
-	if !ok {
-		return fmt.Errorf("region is not a string")
+type jobStorageDataSourceModel struct {
+	ID     types.String `tfsdk:"id"`
+	TeamID types.Int64  `tfsdk:"team_id"`
+	Handle types.String `tfsdk:"handle"`
+	Region types.String `tfsdk:"region"`
+}
+
+func dataSourceJobStorage() datasource.DataSource {
+	return &jobStorageDataSource{}
+}
+
+func (d *jobStorageDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_job_storage"
+}
+
+func (d *jobStorageDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	d.meta = req.ProviderData
+}
+
+func (d *jobStorageDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data jobStorageDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
-	if regionData != "" {
-		region = regionData
+	paperspaceClient := newInternalPaperspaceClient(d.meta)
+	region := paperspaceClient.Region
+	if data.Region.ValueString() != "" {
+		region = regionName(data.Region.ValueString())
 	}
 
-	jobStorage, err := paperspaceClient.GetJobStorageByRegion(teamID, region)
+	teamID := int(data.TeamID.ValueInt64())
+	jobStorage, err := paperspaceClient.GetJobStorageByRegion(ctx, teamID, region)
 	if err != nil {
-		return err
+		resp.Diagnostics.Append(attributeErrorDiagnostic(path.Root("team_id"), fmt.Sprintf("Error reading paperspace job storage for team %d", teamID), err))
+		return
 	}
+
+	// a team without storage in the region is not an error in itself; leave
+	// handle null so configurations can test for it
 	if jobStorage.Handle == "" {
-		return errors.New("Could not find job storage")
+		resp.Diagnostics.AddAttributeWarning(path.Root("region"), "Could not find job storage",
+			fmt.Sprintf("Team %d has no job storage in region %q; handle is left unset.", teamID, region))
+		data.ID = types.StringValue(fmt.Sprintf("%d/%s", teamID, region))
+		data.Handle = types.StringNull()
+		resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+		return
 	}
 
-	d.SetId(jobStorage.Handle)
-	updateJobStorageSchema(d, jobStorage)
+	data.ID = types.StringValue(jobStorage.Handle)
+	updateJobStorageModel(&data, jobStorage)
 
-	return nil
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
-func updateJobStorageSchema(d *schema.ResourceData, jobStorage JobStorage) {
-	d.Set("handle", jobStorage.Handle)
+func updateJobStorageModel(data *jobStorageDataSourceModel, jobStorage JobStorage) {
+	data.Handle = types.StringValue(jobStorage.Handle)
 }
 
-func dataSourceJobStorage() *schema.Resource {
-	return &schema.Resource{
-		Read: dataSourceJobStorageRead,
-		Schema: map[string]*schema.Schema{
-			"team_id": &schema.Schema{
-				Type:     schema.TypeInt,
+func (d *jobStorageDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+			},
+			"team_id": schema.Int64Attribute{
 				Required: true,
 			},
-			"handle": &schema.Schema{
-				Type:     schema.TypeString,
+			"handle": schema.StringAttribute{
 				Optional: true,
+				Computed: true,
 			},
-			"region": &schema.Schema{
-				Type:     schema.TypeString,
-				Optional: true,
+			"region": schema.StringAttribute{
+				Optional:   true,
+				Validators: []validator.String{regionValidator{}},
 			},
 		},
 	}
@@ -0,0 +1,44 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccDataSourceJobStorage_basic(t *testing.T) {
+	api := newFakeAPI(t)
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: api.providerConfig() + fmt.Sprintf(`
+data "paperspace_job_storage" "default" {
+  team_id = %d
+}
+
+data "paperspace_job_storage" "ams1" {
+  team_id = %d
+  region  = "Europe (AMS1)"
+}
+`, fakeTeamID, fakeTeamID),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.paperspace_job_storage.default", "handle", "js-ny2"),
+					resource.TestCheckResourceAttr("data.paperspace_job_storage.ams1", "handle", "js-ams1"),
+				),
+			},
+			{
+				Config: api.providerConfig() + fmt.Sprintf(`
+data "paperspace_job_storage" "test" {
+  team_id = %d
+  region  = "West Coast (CA1)"
+}
+`, fakeTeamID),
+				// no storage in the region is only a warning
+				Check: resource.TestCheckNoResourceAttr("data.paperspace_job_storage.test", "handle"),
+			},
+		},
+	})
+}
@@ -0,0 +1,105 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var machineTypeLookup = lookup{
+	Kind: "machine type",
+	Path: "/machines/getMachineTypes",
+	Attributes: []lookupAttribute{
+		{Name: "id", APIField: "id"},
+		{Name: "name", APIField: "label"},
+		{Name: "region", APIField: "region"},
+		{Name: "cpus", APIField: "cpus", Type: types.Int64Type, NoFilter: true},
+		{Name: "ram", APIField: "ram", NoFilter: true},
+		{Name: "gpu", APIField: "gpu"},
+		{Name: "hourly_price", APIField: "ratePerHour", Type: types.Float64Type, NoFilter: true},
+		{Name: "available", APIField: "isAvailable", Type: types.BoolType},
+	},
+}
+
+func dataSourceMachineTypes() datasource.DataSource {
+	return &lookupDataSource{typeName: "_machine_types", lookup: machineTypeLookup, key: "machine_types"}
+}
+
+// machineTypeCatalog caches the machine types of each region for the life of
+// a provider instance, so planning many machines lists them only once.
+type machineTypeCatalog struct {
+	mu      sync.Mutex
+	regions map[string][]map[string]interface{}
+}
+
+func newMachineTypeCatalog() *machineTypeCatalog {
+	return &machineTypeCatalog{regions: map[string][]map[string]interface{}{}}
+}
+
+func (c *machineTypeCatalog) List(ctx context.Context, paperspaceClient PaperspaceClient, region string) ([]map[string]interface{}, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if items, ok := c.regions[region]; ok {
+		return items, nil
+	}
+
+	items, err := machineTypeLookup.List(ctx, paperspaceClient, url.Values{"region": {region}})
+	if err != nil {
+		return nil, err
+	}
+	c.regions[region] = items
+
+	return items, nil
+}
+
+// validateMachineType checks machineType against the machine types offered
+// in region. An empty or unreachable catalog is not an error: the API still
+// has the last word when the machine is created.
+func validateMachineType(ctx context.Context, config ClientConfig, region, machineType string) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	catalog := config.MachineTypes
+	if catalog == nil {
+		catalog = newMachineTypeCatalog()
+	}
+
+	items, err := catalog.List(ctx, config.Client(), region)
+	if err != nil {
+		diags.AddAttributeWarning(path.Root("machine_type"), "Could not validate paperspace machine type",
+			fmt.Sprintf("Listing the machine types of region %q failed, so machine_type is only checked when the machine is created.\n\n%s", region, errorDetail(err)))
+		return diags
+	}
+	if len(items) == 0 {
+		return diags
+	}
+
+	names := make([]string, 0, len(items))
+	for _, item := range items {
+		name, _ := item["label"].(string)
+		if name != machineType {
+			names = append(names, name)
+			continue
+		}
+
+		if available, ok := item["isAvailable"].(bool); ok && !available {
+			diags.AddAttributeWarning(path.Root("machine_type"), "Paperspace machine type unavailable",
+				fmt.Sprintf("Machine type %q is currently not available in region %q; creating the machine may fail until capacity frees up.", machineType, region))
+		}
+		return diags
+	}
+	sort.Strings(names)
+
+	diags.AddAttributeError(path.Root("machine_type"), "Invalid paperspace machine type",
+		fmt.Sprintf("Machine type %q is not offered in region %q. Available machine types: %s.", machineType, region, strings.Join(names, ", ")))
+
+	return diags
+}
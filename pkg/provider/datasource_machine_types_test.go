@@ -0,0 +1,40 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccDataSourceMachineTypes_basic(t *testing.T) {
+	api := newFakeAPI(t)
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: api.providerConfig() + `
+data "paperspace_machine_types" "all" {
+  region = "East Coast (NY2)"
+}
+
+data "paperspace_machine_types" "available_gpus" {
+  region    = "East Coast (NY2)"
+  gpu       = "Quadro P4000"
+  available = true
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.paperspace_machine_types.all", "machine_types.#", "4"),
+					resource.TestCheckResourceAttr("data.paperspace_machine_types.available_gpus", "machine_types.#", "1"),
+					resource.TestCheckResourceAttr("data.paperspace_machine_types.available_gpus", "machine_types.0.name", "P4000"),
+					resource.TestCheckResourceAttr("data.paperspace_machine_types.available_gpus", "machine_types.0.cpus", "8"),
+					resource.TestCheckResourceAttr("data.paperspace_machine_types.available_gpus", "machine_types.0.ram", "32212254720"),
+					resource.TestCheckResourceAttr("data.paperspace_machine_types.available_gpus", "machine_types.0.hourly_price", "0.51"),
+					resource.TestCheckResourceAttr("data.paperspace_machine_types.available_gpus", "machine_types.0.available", "true"),
+					resource.TestCheckResourceAttr("data.paperspace_machine_types.available_gpus", "ids.0", "mt-ny2-p4000"),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,43 @@
+package provider
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var machineLookup = lookup{
+	Kind: "machine",
+	Path: "/machines/getMachines",
+	Attributes: []lookupAttribute{
+		{Name: "id", APIField: "id"},
+		{Name: "name", APIField: "name"},
+		{Name: "os", APIField: "os"},
+		{Name: "ram", APIField: "ram", NoFilter: true},
+		{Name: "cpus", APIField: "cpus", Type: types.Int64Type, NoFilter: true},
+		{Name: "gpu", APIField: "gpu"},
+		{Name: "storage_total", APIField: "storageTotal", NoFilter: true},
+		{Name: "storage_used", APIField: "storageUsed", NoFilter: true},
+		{Name: "usage_rate", APIField: "usageRate", NoFilter: true},
+		{Name: "shutdown_timeout_in_hours", APIField: "shutdownTimeoutInHours", Type: types.Int64Type, NoFilter: true},
+		{Name: "shutdown_timeout_forces", APIField: "shutdownTimeoutForces", Type: types.BoolType, NoFilter: true},
+		{Name: "perform_auto_snapshot", APIField: "performAutoSnapshot", Type: types.BoolType, NoFilter: true},
+		{Name: "auto_snapshot_frequency", APIField: "autoSnapshotFrequency", NoFilter: true},
+		{Name: "auto_snapshot_save_count", APIField: "autoSnapshotSaveCount", Type: types.Int64Type, NoFilter: true},
+		{Name: "agent_type", APIField: "agentType"},
+		{Name: "dt_created", APIField: "dtCreated"},
+		{Name: "state", APIField: "state"},
+		{Name: "network_id", APIField: "networkId"},
+		{Name: "private_ip_address", APIField: "privateIpAddress"},
+		{Name: "public_ip_address", APIField: "publicIpAddress"},
+		{Name: "region", APIField: "region"},
+		{Name: "user_id", APIField: "userId"},
+		{Name: "team_id", APIField: "teamId"},
+		{Name: "script_id", APIField: "scriptId"},
+		{Name: "dt_last_run", APIField: "dtLastRun", NoFilter: true},
+		{Name: "is_managed", APIField: "isManaged", Type: types.BoolType},
+	},
+}
+
+func dataSourceMachines() datasource.DataSource {
+	return &lookupDataSource{typeName: "_machines", lookup: machineLookup, key: "machines"}
+}
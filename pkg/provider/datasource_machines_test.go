@@ -0,0 +1,45 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccDataSourceMachines_basic(t *testing.T) {
+	api := newFakeAPI(t)
+
+	machine := `
+resource "paperspace_machine" "test" {
+  name         = "tf-acc-machine"
+  machine_type = "C2"
+  size         = 50
+  billing_type = "hourly"
+  template_id  = "tubuntu1"
+}
+`
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories(),
+		CheckDestroy:             testAccCheckMachineDestroy(api),
+		Steps: []resource.TestStep{
+			{
+				Config: api.providerConfig() + machine,
+			},
+			{
+				Config: api.providerConfig() + machine + `
+data "paperspace_machines" "test" {
+  name = paperspace_machine.test.name
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.paperspace_machines.test", "machines.#", "1"),
+					resource.TestCheckResourceAttrPair("data.paperspace_machines.test", "ids.0", "paperspace_machine.test", "id"),
+					resource.TestCheckResourceAttr("data.paperspace_machines.test", "machines.0.state", "ready"),
+					resource.TestCheckResourceAttr("data.paperspace_machines.test", "machines.0.cpus", "8"),
+					resource.TestCheckResourceAttr("data.paperspace_machines.test", "machines.0.shutdown_timeout_in_hours", "24"),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,29 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccDataSourceNetwork_basic(t *testing.T) {
+	api := newFakeAPI(t)
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: api.providerConfig() + `
+data "paperspace_network" "test" {
+  name = "private"
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.paperspace_network.test", "id", "ndef456"),
+					resource.TestCheckResourceAttr("data.paperspace_network.test", "region", "Europe (AMS1)"),
+					resource.TestCheckResourceAttr("data.paperspace_network.test", "network", "10.65.0.0"),
+				),
+			},
+		},
+	})
+}
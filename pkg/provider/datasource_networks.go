@@ -0,0 +1,9 @@
+package provider
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+)
+
+func dataSourceNetworks() datasource.DataSource {
+	return &lookupDataSource{typeName: "_networks", lookup: networkLookup, key: "networks"}
+}
@@ -0,0 +1,29 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccDataSourceNetworks_basic(t *testing.T) {
+	api := newFakeAPI(t)
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: api.providerConfig() + `
+data "paperspace_networks" "ny2" {
+  region = "East Coast (NY2)"
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.paperspace_networks.ny2", "networks.#", "1"),
+					resource.TestCheckResourceAttr("data.paperspace_networks.ny2", "networks.0.name", "default"),
+					resource.TestCheckResourceAttr("data.paperspace_networks.ny2", "ids.0", "nabc123"),
+				),
+			},
+		},
+	})
+}
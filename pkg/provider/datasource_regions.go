@@ -0,0 +1,108 @@
+package provider
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var regionAttributeTypes = map[string]attr.Type{
+	"code": types.StringType,
+	"name": types.StringType,
+	"id":   types.Int64Type,
+}
+
+type regionsDataSource struct{}
+
+type regionsDataSourceModel struct {
+	ID      types.String `tfsdk:"id"`
+	Regions types.List   `tfsdk:"regions"`
+	Codes   types.List   `tfsdk:"codes"`
+	Names   types.List   `tfsdk:"names"`
+}
+
+func dataSourceRegions() datasource.DataSource {
+	return &regionsDataSource{}
+}
+
+func (d *regionsDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_regions"
+}
+
+func (d *regionsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	regions := make([]attr.Value, 0, len(Regions))
+	codes := make([]attr.Value, 0, len(Regions))
+	names := make([]attr.Value, 0, len(Regions))
+	codeStrings := make([]string, 0, len(Regions))
+	for _, region := range Regions {
+		object, diags := types.ObjectValue(regionAttributeTypes, map[string]attr.Value{
+			"code": types.StringValue(region.Code),
+			"name": types.StringValue(region.Name),
+			"id":   types.Int64Value(int64(region.ID)),
+		})
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		regions = append(regions, object)
+		codes = append(codes, types.StringValue(region.Code))
+		names = append(names, types.StringValue(region.Name))
+		codeStrings = append(codeStrings, region.Code)
+	}
+
+	var data regionsDataSourceModel
+	var diags diag.Diagnostics
+	data.ID = types.StringValue(strconv.Itoa(hashcodeString(strings.Join(codeStrings, ","))))
+	data.Regions, diags = types.ListValue(types.ObjectType{AttrTypes: regionAttributeTypes}, regions)
+	resp.Diagnostics.Append(diags...)
+	data.Codes, diags = types.ListValue(types.StringType, codes)
+	resp.Diagnostics.Append(diags...)
+	data.Names, diags = types.ListValue(types.StringType, names)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (d *regionsDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+			},
+			"regions": schema.ListNestedAttribute{
+				Computed: true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"code": schema.StringAttribute{
+							Computed: true,
+						},
+						"name": schema.StringAttribute{
+							Computed: true,
+						},
+						"id": schema.Int64Attribute{
+							Computed: true,
+						},
+					},
+				},
+			},
+			"codes": schema.ListAttribute{
+				Computed:    true,
+				ElementType: types.StringType,
+			},
+			"names": schema.ListAttribute{
+				Computed:    true,
+				ElementType: types.StringType,
+			},
+		},
+	}
+}
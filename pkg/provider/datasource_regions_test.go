@@ -0,0 +1,30 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccDataSourceRegions_basic(t *testing.T) {
+	api := newFakeAPI(t)
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: api.providerConfig() + `
+data "paperspace_regions" "all" {}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.paperspace_regions.all", "regions.#", "3"),
+					resource.TestCheckResourceAttr("data.paperspace_regions.all", "regions.2.code", "ams1"),
+					resource.TestCheckResourceAttr("data.paperspace_regions.all", "regions.2.name", "Europe (AMS1)"),
+					resource.TestCheckResourceAttr("data.paperspace_regions.all", "regions.2.id", "3"),
+					resource.TestCheckResourceAttr("data.paperspace_regions.all", "codes.0", "ny2"),
+					resource.TestCheckResourceAttr("data.paperspace_regions.all", "names.1", "West Coast (CA1)"),
+				),
+			},
+		},
+	})
+}
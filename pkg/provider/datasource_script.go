@@ -0,0 +1,81 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var scriptLookup = lookup{
+	Kind: "script",
+	Path: "/scripts/getScripts",
+	Attributes: []lookupAttribute{
+		{Name: "id", APIField: "id"},
+		{Name: "name", APIField: "name"},
+		{Name: "description", APIField: "description", NoFilter: true},
+		{Name: "owner_type", APIField: "ownerType"},
+		{Name: "owner_id", APIField: "ownerId"},
+		{Name: "dt_created", APIField: "dtCreated", NoFilter: true},
+		{Name: "is_enabled", APIField: "isEnabled", Type: types.BoolType, NoFilter: true},
+		{Name: "run_once", APIField: "runOnce", Type: types.BoolType, NoFilter: true},
+	},
+	fetch: func(ctx context.Context, paperspaceClient PaperspaceClient, query url.Values) (interface{}, error) {
+		return paperspaceClient.GetScripts(ctx, query)
+	},
+}
+
+type scriptDataSource struct {
+	meta interface{}
+}
+
+func dataSourceScript() datasource.DataSource {
+	return &scriptDataSource{}
+}
+
+func (d *scriptDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_script"
+}
+
+func (d *scriptDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	d.meta = req.ProviderData
+}
+
+func (d *scriptDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	scriptLookup.ReadOne(ctx, req, resp, d.meta)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var id types.String
+	resp.Diagnostics.Append(resp.State.GetAttribute(ctx, path.Root("id"), &id)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	paperspaceClient := newInternalPaperspaceClient(d.meta)
+	text, err := paperspaceClient.GetScriptText(ctx, id.ValueString())
+	if err != nil {
+		resp.Diagnostics.Append(errorDiagnostic(fmt.Sprintf("Error reading paperspace script %s text", id.ValueString()), err))
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("script_text"), text)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("script_sha256"), scriptSHA256(text))...)
+}
+
+func (d *scriptDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	s := scriptLookup.Schema()
+	s["script_text"] = schema.StringAttribute{
+		Computed: true,
+	}
+	s["script_sha256"] = schema.StringAttribute{
+		Computed: true,
+	}
+
+	resp.Schema = schema.Schema{Attributes: s}
+}
@@ -0,0 +1,63 @@
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccDataSourceScript_basic(t *testing.T) {
+	api := newFakeAPI(t)
+	config := api.providerConfig() + `
+resource "paperspace_script" "test" {
+  name        = "tf-acc-script"
+  description = "installs things"
+  script_text = "#!/bin/bash\necho hello"
+  run_once    = true
+}
+`
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: config + `
+data "paperspace_script" "test" {
+  id = paperspace_script.test.id
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair("data.paperspace_script.test", "id", "paperspace_script.test", "id"),
+					resource.TestCheckResourceAttr("data.paperspace_script.test", "name", "tf-acc-script"),
+					resource.TestCheckResourceAttr("data.paperspace_script.test", "description", "installs things"),
+					resource.TestCheckResourceAttr("data.paperspace_script.test", "owner_type", "team"),
+					resource.TestCheckResourceAttr("data.paperspace_script.test", "owner_id", "te1001"),
+					resource.TestCheckResourceAttr("data.paperspace_script.test", "is_enabled", "true"),
+					resource.TestCheckResourceAttr("data.paperspace_script.test", "run_once", "true"),
+					resource.TestCheckResourceAttr("data.paperspace_script.test", "script_text", "#!/bin/bash\necho hello"),
+					resource.TestCheckResourceAttrPair("data.paperspace_script.test", "script_sha256", "paperspace_script.test", "script_sha256"),
+				),
+			},
+			{
+				Config: config + `
+data "paperspace_script" "test" {
+  name = "tf-acc-script"
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair("data.paperspace_script.test", "id", "paperspace_script.test", "id"),
+					resource.TestCheckResourceAttr("data.paperspace_script.test", "script_text", "#!/bin/bash\necho hello"),
+				),
+			},
+			{
+				Config: config + `
+data "paperspace_script" "test" {
+  name = "no-such-script"
+}
+`,
+				ExpectError: regexp.MustCompile("no script found matching given properties"),
+			},
+		},
+	})
+}
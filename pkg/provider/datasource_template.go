@@ -1,191 +1,135 @@
 package provider
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
-	"log"
-	"net/http/httputil"
 	"net/url"
-
-	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
-func dataSourceTemplateRead(d *schema.ResourceData, m interface{}) error {
-	paperspaceClient := newInternalPaperspaceClient(m)
-
-	log.Printf("[INFO] paperspace dataSourceTemplateRead Client ready")
+var templateLookup = lookup{
+	Kind: "template",
+	Path: "/templates/getTemplates",
+	Attributes: []lookupAttribute{
+		{Name: "id", APIField: "id"},
+		{Name: "name", APIField: "name"},
+		{Name: "label", APIField: "label"},
+		{Name: "os", APIField: "os"},
+		{Name: "dt_created", APIField: "dtCreated"},
+		{Name: "team_id", APIField: "teamId"},
+		{Name: "user_id", APIField: "userId"},
+		{Name: "region", APIField: "region"},
+	},
+	fetch: func(ctx context.Context, paperspaceClient PaperspaceClient, query url.Values) (interface{}, error) {
+		return paperspaceClient.GetTemplates(ctx, query)
+	},
+}
 
-	queryParam := false
-	queryStr := "?"
-	id, ok := d.GetOk("id")
-	if ok {
-		queryStr += "id=" + url.QueryEscape(id.(string))
-		queryParam = true
-	}
-	name, ok := d.GetOk("name")
-	if ok {
-		if queryParam {
-			queryStr += "&"
-		}
-		queryStr += "name=" + url.QueryEscape(name.(string))
-		queryParam = true
-	}
-	label, ok := d.GetOk("label")
-	if ok {
-		if queryParam {
-			queryStr += "&"
-		}
-		queryStr += "label=" + url.QueryEscape(label.(string))
-		queryParam = true
-	}
-	os, ok := d.GetOk("os")
-	if ok {
-		if queryParam {
-			queryStr += "&"
-		}
-		queryStr += "os=" + url.QueryEscape(os.(string))
-		queryParam = true
-	}
-	dtCreated, ok := d.GetOk("dt_created")
-	if ok {
-		if queryParam {
-			queryStr += "&"
-		}
-		queryStr += "dtCreated=" + url.QueryEscape(dtCreated.(string))
-		queryParam = true
-	}
-	teamId, ok := d.GetOk("team_id")
-	if ok {
-		if queryParam {
-			queryStr += "&"
-		}
-		queryStr += "teamId=" + url.QueryEscape(teamId.(string))
-		queryParam = true
-	}
-	userId, ok := d.GetOk("user_id")
-	if ok {
-		if queryParam {
-			queryStr += "&"
-		}
-		queryStr += "userId=" + url.QueryEscape(userId.(string))
-		queryParam = true
-	}
-	region, ok := d.GetOk("region")
-	if ok {
-		if queryParam {
-			queryStr += "&"
-		}
-		queryStr += "region=" + url.QueryEscape(region.(string))
-		queryParam = true
-	}
-	if !queryParam {
-		return fmt.Errorf("Error reading paperspace template: must specify query filter properties")
+// listTemplates returns the templates matching the API filters set in config,
+// narrowed down client-side by name_regex if set. filters describes both.
+func listTemplates(ctx context.Context, config tfsdk.Config, paperspaceClient PaperspaceClient, requireFilter bool) (items []map[string]interface{}, filters string, diags diag.Diagnostics) {
+	query, diags := templateLookup.Query(ctx, config)
+	if diags.HasError() {
+		return nil, "", diags
 	}
 
-	url := fmt.Sprintf("%s/templates/getTemplates%s", paperspaceClient.APIHost, queryStr)
-	req, err := paperspaceClient.NewHttpRequest("GET", url, nil)
-	if err != nil {
-		return fmt.Errorf("Error constructing GetTemplates request: %s", err)
+	var nameRegex types.String
+	diags.Append(config.GetAttribute(ctx, path.Root("name_regex"), &nameRegex)...)
+	if diags.HasError() {
+		return nil, "", diags
 	}
-	requestDump, err := httputil.DumpRequest(req, true)
-	if err != nil {
-		return fmt.Errorf("Error constructing GetTemplates request: %s", err)
+	hasNameRegex := nameRegex.ValueString() != ""
+
+	if requireFilter && len(query) == 0 && !hasNameRegex {
+		diags.AddError("Missing paperspace template filter",
+			fmt.Sprintf("must specify query filter properties: set name_regex or at least one of %s", strings.Join(templateLookup.filterNames(), ", ")))
+		return nil, "", diags
 	}
-	log.Print("[INFO] Request:", string(requestDump))
 
-	resp, err := paperspaceClient.HttpClient.Do(req)
+	items, err := templateLookup.List(ctx, paperspaceClient, query)
 	if err != nil {
-		return fmt.Errorf("Error reading paperspace template: %s", err)
+		diags.Append(errorDiagnostic("Error reading paperspace template", err))
+		return nil, "", diags
 	}
-	defer resp.Body.Close()
 
-	statusCode := resp.StatusCode
-	log.Printf("[INFO] paperspace dataSourceTemplateRead StatusCode: %v", statusCode)
-	if statusCode == 404 {
-		return fmt.Errorf("Error reading paperspace template: templates not found")
-	}
-	if statusCode != 200 {
-		responseDump, _ := httputil.DumpResponse(resp, true)
-		return fmt.Errorf("Error reading paperspace template: Response: %s", string(responseDump))
+	if hasNameRegex {
+		re, err := regexp.Compile(nameRegex.ValueString())
+		if err != nil {
+			diags.AddAttributeError(path.Root("name_regex"), "Invalid paperspace template name_regex", err.Error())
+			return nil, "", diags
+		}
+		items = filterByRegex(items, "name", re)
+		query.Set("name_regex", nameRegex.ValueString())
 	}
 
-	var f interface{}
-	err = json.NewDecoder(resp.Body).Decode(&f)
-	if err != nil {
-		return fmt.Errorf("Error decoding GetTemplate response body: %s", err)
-	}
-	LogHttpResponse("paperspace dataSourceTemplateRead", req.URL, resp, f, err)
+	return items, query.Encode(), diags
+}
 
-	mpa := f.([]interface{})
-	if len(mpa) > 1 {
-		return fmt.Errorf("Error reading paperspace template: found more than one template matching given properties")
-	}
-	if len(mpa) == 0 {
-		return fmt.Errorf("Error reading paperspace template: no template found matching given properties")
-	}
+type templateDataSource struct {
+	meta interface{}
+}
 
-	mp, ok := mpa[0].(map[string]interface{})
-	if !ok {
-		return fmt.Errorf("Error unmarshalling paperspace template read response: no templates not found")
-	}
+func dataSourceTemplate() datasource.DataSource {
+	return &templateDataSource{}
+}
 
-	idr, _ := mp["id"].(string)
-	if idr == "" {
-		return fmt.Errorf("Error unmarshalling paperspace template read response: no template id found for template")
+func (d *templateDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_template"
+}
+
+func (d *templateDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	d.meta = req.ProviderData
+}
+
+func (d *templateDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	paperspaceClient := newInternalPaperspaceClient(d.meta)
+
+	items, filters, diags := listTemplates(ctx, req.Config, paperspaceClient, true)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
-	log.Printf("[INFO] paperspace dataSourceTemplateRead template id: %v", idr)
+	var mostRecentOnly types.Bool
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("most_recent"), &mostRecentOnly)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-	SetResData(d, mp, "name")
-	SetResData(d, mp, "label")
-	SetResData(d, mp, "os")
-	SetResDataFrom(d, mp, "dt_created", "dtCreated")
-	SetResDataFrom(d, mp, "team_id", "teamId")
-	SetResDataFrom(d, mp, "user_id", "userId")
-	SetResData(d, mp, "region")
+	if mostRecentOnly.ValueBool() && len(items) > 1 {
+		items = []map[string]interface{}{mostRecent(items, "dtCreated")}
+	}
 
-	d.SetId(idr)
+	item, err := templateLookup.one(items, filters)
+	if err != nil {
+		resp.Diagnostics.AddError("Error reading paperspace template", err.Error())
+		return
+	}
 
-	return nil
+	resp.State.Raw = req.Config.Raw.Copy()
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("most_recent"), mostRecentOnly.ValueBool())...)
+	resp.Diagnostics.Append(templateLookup.Set(ctx, &resp.State, item)...)
 }
 
-func dataSourceTemplate() *schema.Resource {
-	return &schema.Resource{
-		Read: dataSourceTemplateRead,
-
-		Schema: map[string]*schema.Schema{
-			"id": &schema.Schema{
-				Type:     schema.TypeString,
-				Optional: true,
-			},
-			"name": &schema.Schema{
-				Type:     schema.TypeString,
-				Optional: true,
-			},
-			"label": &schema.Schema{
-				Type:     schema.TypeString,
-				Optional: true,
-			},
-			"os": &schema.Schema{
-				Type:     schema.TypeString,
-				Optional: true,
-			},
-			"dt_created": &schema.Schema{
-				Type:     schema.TypeString,
-				Optional: true,
-			},
-			"team_id": &schema.Schema{
-				Type:     schema.TypeString,
-				Optional: true,
-			},
-			"user_id": &schema.Schema{
-				Type:     schema.TypeString,
-				Optional: true,
-			},
-			"region": &schema.Schema{
-				Type:     schema.TypeString,
-				Optional: true,
-			},
-		},
+func (d *templateDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	s := templateLookup.Schema()
+	s["name_regex"] = schema.StringAttribute{
+		Optional:   true,
+		Validators: []validator.String{regexpValidator{}},
 	}
+	s["most_recent"] = schema.BoolAttribute{
+		Optional: true,
+		Computed: true,
+	}
+
+	resp.Schema = schema.Schema{Attributes: s}
 }
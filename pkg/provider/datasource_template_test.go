@@ -0,0 +1,54 @@
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccDataSourceTemplate_basic(t *testing.T) {
+	api := newFakeAPI(t)
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: api.providerConfig() + `
+data "paperspace_template" "test" {
+  label = "Windows 10"
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.paperspace_template.test", "id", "twindows1"),
+					resource.TestCheckResourceAttr("data.paperspace_template.test", "os", "Windows 10 (Server 2016)"),
+				),
+			},
+			{
+				Config: api.providerConfig() + `
+data "paperspace_template" "test" {
+  name_regex  = "^Ubuntu"
+  most_recent = true
+}
+`,
+				Check: resource.TestCheckResourceAttr("data.paperspace_template.test", "id", "tubuntu2"),
+			},
+			{
+				Config: api.providerConfig() + `
+data "paperspace_template" "test" {
+  name_regex = "^Ubuntu"
+}
+`,
+				ExpectError: regexp.MustCompile("found more than one template"),
+			},
+			{
+				Config: api.providerConfig() + `
+data "paperspace_template" "test" {
+  name = "CentOS"
+}
+`,
+				ExpectError: regexp.MustCompile("no template found"),
+			},
+		},
+	})
+}
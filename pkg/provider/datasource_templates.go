@@ -0,0 +1,48 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+)
+
+type templatesDataSource struct {
+	meta interface{}
+}
+
+func dataSourceTemplates() datasource.DataSource {
+	return &templatesDataSource{}
+}
+
+func (d *templatesDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_templates"
+}
+
+func (d *templatesDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	d.meta = req.ProviderData
+}
+
+func (d *templatesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	paperspaceClient := newInternalPaperspaceClient(d.meta)
+
+	items, filters, diags := listTemplates(ctx, req.Config, paperspaceClient, false)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.State.Raw = req.Config.Raw.Copy()
+	resp.Diagnostics.Append(templateLookup.SetList(ctx, &resp.State, "templates", filters, items)...)
+}
+
+func (d *templatesDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	s := templateLookup.ListSchema("templates")
+	s["name_regex"] = schema.StringAttribute{
+		Optional:   true,
+		Validators: []validator.String{regexpValidator{}},
+	}
+
+	resp.Schema = schema.Schema{Attributes: s}
+}
@@ -0,0 +1,30 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccDataSourceTemplates_basic(t *testing.T) {
+	api := newFakeAPI(t)
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: api.providerConfig() + `
+data "paperspace_templates" "ubuntu" {
+  name_regex = "^Ubuntu"
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.paperspace_templates.ubuntu", "templates.#", "2"),
+					resource.TestCheckResourceAttr("data.paperspace_templates.ubuntu", "ids.0", "tubuntu1"),
+					resource.TestCheckResourceAttr("data.paperspace_templates.ubuntu", "ids.1", "tubuntu2"),
+					resource.TestCheckResourceAttr("data.paperspace_templates.ubuntu", "templates.1.name", "Ubuntu 20.04 Server"),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,38 @@
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccDataSourceUser_basic(t *testing.T) {
+	api := newFakeAPI(t)
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: api.providerConfig() + `
+data "paperspace_user" "test" {
+  email = "jane@example.com"
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.paperspace_user.test", "id", fakeUserID),
+					resource.TestCheckResourceAttr("data.paperspace_user.test", "firstname", "Jane"),
+					resource.TestCheckResourceAttr("data.paperspace_user.test", "team_id", "te1001"),
+				),
+			},
+			{
+				Config: api.providerConfig() + `
+data "paperspace_user" "test" {
+  lastname = "Doe"
+}
+`,
+				ExpectError: regexp.MustCompile("found more than one user"),
+			},
+		},
+	})
+}
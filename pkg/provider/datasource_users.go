@@ -0,0 +1,9 @@
+package provider
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+)
+
+func dataSourceUsers() datasource.DataSource {
+	return &lookupDataSource{typeName: "_users", lookup: userLookup, key: "users"}
+}
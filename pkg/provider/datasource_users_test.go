@@ -0,0 +1,33 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccDataSourceUsers_basic(t *testing.T) {
+	api := newFakeAPI(t)
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: api.providerConfig() + `
+data "paperspace_users" "all" {}
+
+data "paperspace_users" "john" {
+  firstname = "John"
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.paperspace_users.all", "users.#", "2"),
+					resource.TestCheckResourceAttr("data.paperspace_users.all", "ids.#", "2"),
+					resource.TestCheckResourceAttr("data.paperspace_users.john", "users.#", "1"),
+					resource.TestCheckResourceAttr("data.paperspace_users.john", "users.0.email", "john@example.com"),
+					resource.TestCheckResourceAttr("data.paperspace_users.john", "ids.0", "udef456"),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,62 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Paperspace/paperspace-go"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+)
+
+// errorDetail renders err as the detail of a diagnostic. API errors spell out
+// the request, the status and the response body, which usually says what the
+// API objected to.
+func errorDetail(err error) string {
+	var apiError *APIError
+	if errors.As(err, &apiError) {
+		detail := err.Error()
+		if apiError.Body != "" && apiError.Body != apiError.Message {
+			detail = fmt.Sprintf("%s\n\nAPI response body:\n%s", detail, apiError.Body)
+		}
+
+		return detail
+	}
+
+	var paperspaceErrorPtr *paperspace.PaperspaceError
+	if errors.As(err, &paperspaceErrorPtr) {
+		return paperspaceErrorDetail(err, *paperspaceErrorPtr)
+	}
+
+	var paperspaceError paperspace.PaperspaceError
+	if errors.As(err, &paperspaceError) {
+		return paperspaceErrorDetail(err, paperspaceError)
+	}
+
+	return err.Error()
+}
+
+func paperspaceErrorDetail(err error, paperspaceError paperspace.PaperspaceError) string {
+	detail := err.Error()
+	if paperspaceError.Status != 0 {
+		detail = fmt.Sprintf("%s (status %d", detail, paperspaceError.Status)
+		if paperspaceError.Name != "" {
+			detail = fmt.Sprintf("%s, %s", detail, paperspaceError.Name)
+		}
+		detail += ")"
+	}
+
+	return detail
+}
+
+// errorDiagnostic returns an error diagnostic not tied to any attribute.
+func errorDiagnostic(summary string, err error) diag.Diagnostic {
+	return diag.NewErrorDiagnostic(summary, errorDetail(err))
+}
+
+// attributeErrorDiagnostic returns an error diagnostic pointing at the
+// attribute at p, so Terraform can show which part of the configuration
+// caused it.
+func attributeErrorDiagnostic(p path.Path, summary string, err error) diag.Diagnostic {
+	return diag.NewAttributeErrorDiagnostic(p, summary, errorDetail(err))
+}
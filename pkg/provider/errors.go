@@ -0,0 +1,133 @@
+package provider
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/Paperspace/paperspace-go"
+)
+
+// APIError is returned by PaperspaceClient for any non-2xx API response.
+type APIError struct {
+	StatusCode int
+	Method     string
+	URL        string
+	Code       string
+	Message    string
+	Body       string
+}
+
+func (e *APIError) Error() string {
+	msg := e.Message
+	if msg == "" {
+		msg = e.Body
+	}
+	if msg == "" {
+		msg = http.StatusText(e.StatusCode)
+	}
+	if e.Code != "" {
+		msg = fmt.Sprintf("%s: %s", e.Code, msg)
+	}
+
+	return fmt.Sprintf("Error on %s %s: status %d: %s", e.Method, e.URL, e.StatusCode, msg)
+}
+
+// apiErrorResponse covers the error bodies the API is known to send, either
+// wrapped in an "error" object or at the top level.
+type apiErrorResponse struct {
+	Error *apiErrorDetail `json:"error"`
+	apiErrorDetail
+}
+
+type apiErrorDetail struct {
+	Name    string `json:"name"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func newAPIError(method, url string, statusCode int, body []byte) *APIError {
+	apiError := &APIError{
+		StatusCode: statusCode,
+		Method:     method,
+		URL:        url,
+		Body:       strings.TrimSpace(redactJSON(body)),
+	}
+
+	var response apiErrorResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return apiError
+	}
+
+	detail := response.apiErrorDetail
+	if response.Error != nil {
+		detail = *response.Error
+	}
+
+	apiError.Code = detail.Code
+	if apiError.Code == "" {
+		apiError.Code = detail.Name
+	}
+	apiError.Message = detail.Message
+
+	return apiError
+}
+
+// newNotFoundError is used when the API answers successfully but the object
+// we asked for is missing from the response.
+func newNotFoundError(method, url, message string) *APIError {
+	return &APIError{
+		StatusCode: http.StatusNotFound,
+		Method:     method,
+		URL:        url,
+		Message:    message,
+	}
+}
+
+// ErrorStatusCode returns the HTTP status carried by err, understanding both
+// APIError and paperspace.PaperspaceError, or 0 if there is none.
+func ErrorStatusCode(err error) int {
+	var apiError *APIError
+	if errors.As(err, &apiError) {
+		return apiError.StatusCode
+	}
+
+	var paperspaceErrorPtr *paperspace.PaperspaceError
+	if errors.As(err, &paperspaceErrorPtr) {
+		return paperspaceErrorPtr.Status
+	}
+
+	var paperspaceError paperspace.PaperspaceError
+	if errors.As(err, &paperspaceError) {
+		return paperspaceError.Status
+	}
+
+	return 0
+}
+
+func IsNotFound(err error) bool {
+	return ErrorStatusCode(err) == http.StatusNotFound
+}
+
+func IsConflict(err error) bool {
+	return ErrorStatusCode(err) == http.StatusConflict
+}
+
+func IsRateLimited(err error) bool {
+	return ErrorStatusCode(err) == http.StatusTooManyRequests
+}
+
+// IsRetryable reports whether a failed call may succeed if repeated: the
+// object is busy (conflict), we are being rate limited, the API had a
+// server-side failure, or the request never got a response at all.
+func IsRetryable(err error) bool {
+	if IsConflict(err) || IsRateLimited(err) {
+		return true
+	}
+
+	statusCode := ErrorStatusCode(err)
+
+	return statusCode == 0 || statusCode >= 500
+}
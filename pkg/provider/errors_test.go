@@ -0,0 +1,133 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/Paperspace/paperspace-go"
+)
+
+func TestNewAPIError(t *testing.T) {
+	cases := []struct {
+		name       string
+		statusCode int
+		body       string
+		code       string
+		message    string
+		errorText  string
+	}{
+		{
+			name:       "wrapped json error",
+			statusCode: http.StatusNotFound,
+			body:       `{"error":{"name":"NotFound","message":"Machine not found"}}`,
+			code:       "NotFound",
+			message:    "Machine not found",
+			errorText:  "Error on GET https://api.example.com/machines/ps1: status 404: NotFound: Machine not found",
+		},
+		{
+			name:       "top level json error",
+			statusCode: http.StatusBadRequest,
+			body:       `{"code":"InvalidArgument","name":"ValidationError","message":"size is invalid"}`,
+			code:       "InvalidArgument",
+			message:    "size is invalid",
+			errorText:  "Error on GET https://api.example.com/machines/ps1: status 400: InvalidArgument: size is invalid",
+		},
+		{
+			name:       "plain text",
+			statusCode: http.StatusBadGateway,
+			body:       "Bad Gateway\n",
+			errorText:  "Error on GET https://api.example.com/machines/ps1: status 502: Bad Gateway",
+		},
+		{
+			name:       "empty body",
+			statusCode: http.StatusServiceUnavailable,
+			errorText:  "Error on GET https://api.example.com/machines/ps1: status 503: Service Unavailable",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := newAPIError(http.MethodGet, "https://api.example.com/machines/ps1", tc.statusCode, []byte(tc.body))
+
+			if err.StatusCode != tc.statusCode {
+				t.Errorf("expected status %d, got %d", tc.statusCode, err.StatusCode)
+			}
+			if err.Code != tc.code {
+				t.Errorf("expected code %q, got %q", tc.code, err.Code)
+			}
+			if err.Message != tc.message {
+				t.Errorf("expected message %q, got %q", tc.message, err.Message)
+			}
+			if got := err.Error(); got != tc.errorText {
+				t.Errorf("expected %q, got %q", tc.errorText, got)
+			}
+		})
+	}
+}
+
+func TestErrorStatusCode(t *testing.T) {
+	cases := []struct {
+		name       string
+		err        error
+		statusCode int
+		notFound   bool
+		retryable  bool
+	}{
+		{
+			name:       "api error",
+			err:        newAPIError(http.MethodGet, "https://api.example.com/machines/ps1", http.StatusNotFound, nil),
+			statusCode: http.StatusNotFound,
+			notFound:   true,
+		},
+		{
+			name:       "wrapped api error",
+			err:        fmt.Errorf("reading machine: %w", newAPIError(http.MethodGet, "https://api.example.com/machines/ps1", http.StatusConflict, nil)),
+			statusCode: http.StatusConflict,
+			retryable:  true,
+		},
+		{
+			name:       "paperspace error value",
+			err:        paperspace.PaperspaceError{Status: http.StatusNotFound, Message: "not found"},
+			statusCode: http.StatusNotFound,
+			notFound:   true,
+		},
+		{
+			name:       "paperspace error pointer",
+			err:        &paperspace.PaperspaceError{Status: http.StatusTooManyRequests, Message: "slow down"},
+			statusCode: http.StatusTooManyRequests,
+			retryable:  true,
+		},
+		{
+			name:       "wrapped paperspace error value",
+			err:        fmt.Errorf("listing machines: %w", paperspace.PaperspaceError{Status: http.StatusInternalServerError}),
+			statusCode: http.StatusInternalServerError,
+			retryable:  true,
+		},
+		{
+			name:       "client error",
+			err:        newAPIError(http.MethodPost, "https://api.example.com/machines/createSingleMachinePublic", http.StatusBadRequest, nil),
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:      "no status",
+			err:       errors.New("connection reset by peer"),
+			retryable: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := ErrorStatusCode(tc.err); got != tc.statusCode {
+				t.Errorf("expected status %d, got %d", tc.statusCode, got)
+			}
+			if got := IsNotFound(tc.err); got != tc.notFound {
+				t.Errorf("expected IsNotFound %v, got %v", tc.notFound, got)
+			}
+			if got := IsRetryable(tc.err); got != tc.retryable {
+				t.Errorf("expected IsRetryable %v, got %v", tc.retryable, got)
+			}
+		})
+	}
+}
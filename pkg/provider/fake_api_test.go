@@ -0,0 +1,536 @@
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+const (
+	fakeAPIKey  = "fake-api-key"
+	fakeTeamID  = 1001
+	fakeUserID  = "uabc123"
+	fakeRegion  = "East Coast (NY2)"
+	fakeDeleted = "<deleted>"
+)
+
+// fakeMachine is a machine held by fakeAPI. Every read of the machine
+// advances it one step through pending, so callers polling for a state see
+// the intermediate state at least once, as they would against the real API.
+type fakeMachine struct {
+	fields  map[string]interface{}
+	pending []string
+}
+
+type fakeScript struct {
+	fields map[string]interface{}
+	text   string
+}
+
+// fakeAPI is an in-process stand-in for the Paperspace API covering every
+// endpoint the provider uses. It is stateful: objects created through it can
+// be read, listed, updated and deleted until the test ends.
+type fakeAPI struct {
+	*httptest.Server
+
+	mu                sync.Mutex
+	lastID            int
+	machines          map[string]*fakeMachine
+	scripts           map[string]*fakeScript
+	teamNetworks      map[int][]map[string]interface{}
+	pendingNetworks   map[int][]map[string]interface{}
+	autoscalingGroups map[string]map[string]interface{}
+	networks          []map[string]interface{}
+	machineTypes      []map[string]interface{}
+	templates         []map[string]interface{}
+	users             []map[string]interface{}
+	jobStorages       map[int][]JobStorage
+}
+
+// newFakeAPI starts a fake API seeded with a few templates, users, networks,
+// machine types and job storages. It is shut down when the test ends.
+func newFakeAPI(t *testing.T) *fakeAPI {
+	f := &fakeAPI{
+		machines:          map[string]*fakeMachine{},
+		scripts:           map[string]*fakeScript{},
+		teamNetworks:      map[int][]map[string]interface{}{},
+		pendingNetworks:   map[int][]map[string]interface{}{},
+		autoscalingGroups: map[string]map[string]interface{}{},
+		networks: []map[string]interface{}{
+			{"id": "nabc123", "name": "default", "region": fakeRegion, "dtCreated": "2020-01-02T00:00:00.000Z", "network": "10.64.0.0", "netmask": "255.255.240.0", "teamId": "te1001"},
+			{"id": "ndef456", "name": "private", "region": "Europe (AMS1)", "dtCreated": "2020-03-04T00:00:00.000Z", "network": "10.65.0.0", "netmask": "255.255.240.0", "teamId": "te1001"},
+		},
+		machineTypes: []map[string]interface{}{
+			{"id": "mt-ny2-c2", "label": "C2", "region": fakeRegion, "cpus": 1, "ram": "4294967296", "gpu": "", "ratePerHour": 0.009, "isAvailable": true},
+			{"id": "mt-ny2-p4000", "label": "P4000", "region": fakeRegion, "cpus": 8, "ram": "32212254720", "gpu": "Quadro P4000", "ratePerHour": 0.51, "isAvailable": true},
+			{"id": "mt-ny2-p5000", "label": "P5000", "region": fakeRegion, "cpus": 8, "ram": "32212254720", "gpu": "Quadro P5000", "ratePerHour": 0.78, "isAvailable": true},
+			{"id": "mt-ny2-a4000", "label": "A4000", "region": fakeRegion, "cpus": 8, "ram": "48318382080", "gpu": "NVIDIA RTX A4000", "ratePerHour": 0.76, "isAvailable": false},
+			{"id": "mt-ams1-c2", "label": "C2", "region": "Europe (AMS1)", "cpus": 1, "ram": "4294967296", "gpu": "", "ratePerHour": 0.009, "isAvailable": true},
+		},
+		templates: []map[string]interface{}{
+			{"id": "tubuntu1", "name": "Ubuntu 18.04 Server", "label": "Ubuntu 18.04 Server", "os": "Ubuntu 18.04.3 LTS; uname: 4.15.0-20-generic", "dtCreated": "2019-01-01T00:00:00.000Z", "teamId": "te1001", "userId": fakeUserID, "region": fakeRegion},
+			{"id": "tubuntu2", "name": "Ubuntu 20.04 Server", "label": "Ubuntu 20.04 Server", "os": "Ubuntu 20.04.1 LTS; uname: 5.4.0-42-generic", "dtCreated": "2020-06-01T00:00:00.000Z", "teamId": "te1001", "userId": fakeUserID, "region": fakeRegion},
+			{"id": "twindows1", "name": "Windows 10", "label": "Windows 10", "os": "Windows 10 (Server 2016)", "dtCreated": "2019-06-01T00:00:00.000Z", "teamId": "te1001", "userId": fakeUserID, "region": fakeRegion},
+		},
+		users: []map[string]interface{}{
+			{"id": fakeUserID, "email": "jane@example.com", "firstname": "Jane", "lastname": "Doe", "dtCreated": "2019-01-01T00:00:00.000Z", "teamId": "te1001"},
+			{"id": "udef456", "email": "john@example.com", "firstname": "John", "lastname": "Doe", "dtCreated": "2019-02-01T00:00:00.000Z", "teamId": "te1001"},
+		},
+		jobStorages: map[int][]JobStorage{
+			fakeTeamID: {
+				{Handle: "js-ny2", TeamID: fakeTeamID, Server: JobStorageServer{IP: "10.0.0.10", StorageRegion: StorageRegion{Name: fakeRegion}}},
+				{Handle: "js-ams1", TeamID: fakeTeamID, Server: JobStorageServer{IP: "10.0.0.11", StorageRegion: StorageRegion{Name: "Europe (AMS1)"}}},
+			},
+		},
+	}
+
+	f.Server = httptest.NewServer(http.HandlerFunc(f.serveHTTP))
+	t.Cleanup(f.Close)
+
+	return f
+}
+
+// providerConfig returns a provider block pointing at the fake API.
+func (f *fakeAPI) providerConfig() string {
+	return fmt.Sprintf(`
+provider "paperspace" {
+  api_key             = %q
+  api_host            = %q
+  region              = %q
+  requests_per_second = 0
+}
+`, fakeAPIKey, f.URL, fakeRegion)
+}
+
+func (f *fakeAPI) newID(prefix string) string {
+	f.lastID++
+	return fmt.Sprintf("%s%d", prefix, f.lastID)
+}
+
+func (f *fakeAPI) machineExists(id string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	_, ok := f.machines[id]
+	return ok
+}
+
+func (f *fakeAPI) scriptExists(id string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	_, ok := f.scripts[id]
+	return ok
+}
+
+func (f *fakeAPI) networkExists(id string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, namedNetwork := range f.teamNetworks[fakeTeamID] {
+		if fmt.Sprint(namedNetwork["network"].(map[string]interface{})["id"]) == id {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (f *fakeAPI) autoscalingGroupExists(id string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	_, ok := f.autoscalingGroups[id]
+	return ok
+}
+
+func (f *fakeAPI) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("x-api-key") != fakeAPIKey {
+		writeFakeError(w, http.StatusUnauthorized, "Invalid API key")
+		return
+	}
+
+	var body map[string]interface{}
+	if r.Body != nil && r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil && err.Error() != "EOF" {
+			writeFakeError(w, http.StatusBadRequest, fmt.Sprintf("Invalid JSON body: %s", err))
+			return
+		}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	path := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	query := r.URL.Query()
+
+	switch {
+	case r.Method == "GET" && r.URL.Path == "/machines/getMachinePublic":
+		f.getMachine(w, query.Get("machineId"))
+	case r.Method == "GET" && r.URL.Path == "/machines/getMachines":
+		var machines []map[string]interface{}
+		for _, machine := range f.machines {
+			machines = append(machines, machine.fields)
+		}
+		writeFakeJSON(w, http.StatusOK, filterFakeItems(machines, query))
+	case r.Method == "GET" && r.URL.Path == "/machines/getMachineTypes":
+		writeFakeJSON(w, http.StatusOK, filterFakeItems(f.machineTypes, query))
+	case r.Method == "POST" && r.URL.Path == "/machines/createSingleMachinePublic":
+		f.createMachine(w, body)
+	case r.Method == "POST" && len(path) == 3 && path[0] == "machines":
+		f.machineAction(w, path[1], path[2], body)
+
+	case r.Method == "POST" && r.URL.Path == "/scripts/createScript":
+		f.createScript(w, body)
+	case r.Method == "GET" && r.URL.Path == "/scripts/getScript":
+		script, ok := f.scripts[query.Get("scriptId")]
+		if !ok {
+			writeFakeError(w, http.StatusNotFound, "Script not found")
+			return
+		}
+		writeFakeJSON(w, http.StatusOK, script.fields)
+	case r.Method == "GET" && r.URL.Path == "/scripts/getScripts":
+		var scripts []map[string]interface{}
+		for _, script := range f.scripts {
+			scripts = append(scripts, script.fields)
+		}
+		writeFakeJSON(w, http.StatusOK, filterFakeItems(scripts, query))
+	case r.Method == "GET" && r.URL.Path == "/scripts/getScriptText":
+		script, ok := f.scripts[query.Get("scriptId")]
+		if !ok {
+			writeFakeError(w, http.StatusNotFound, "Script not found")
+			return
+		}
+		writeFakeJSON(w, http.StatusOK, script.text)
+	case r.Method == "POST" && len(path) == 3 && path[0] == "scripts" && path[2] == "destroy":
+		if _, ok := f.scripts[path[1]]; !ok {
+			writeFakeError(w, http.StatusNotFound, "Script not found")
+			return
+		}
+		delete(f.scripts, path[1])
+		w.WriteHeader(http.StatusNoContent)
+
+	case r.Method == "POST" && len(path) == 3 && path[0] == "teams" && path[2] == "createPrivateNetwork":
+		f.createTeamNetwork(w, path[1], body)
+	case r.Method == "GET" && len(path) == 3 && path[0] == "teams" && path[2] == "getNetworks":
+		f.getTeamNetworks(w, path[1])
+	case r.Method == "GET" && r.URL.Path == "/networks/getNetworks":
+		writeFakeJSON(w, http.StatusOK, filterFakeItems(f.networks, query))
+	case r.Method == "DELETE" && len(path) == 2 && path[0] == "networks":
+		f.deleteTeamNetwork(w, path[1])
+
+	case r.Method == "GET" && r.URL.Path == "/templates/getTemplates":
+		writeFakeJSON(w, http.StatusOK, filterFakeItems(f.templates, query))
+	case r.Method == "GET" && r.URL.Path == "/users/getUsers":
+		writeFakeJSON(w, http.StatusOK, filterFakeItems(f.users, query))
+	case r.Method == "GET" && len(path) == 4 && path[0] == "accounts" && path[3] == "getJobStorage":
+		teamID, _ := strconv.Atoi(path[2])
+		writeFakeJSON(w, http.StatusOK, f.jobStorages[teamID])
+
+	case r.Method == "POST" && r.URL.Path == "/autoscalingGroups":
+		f.createAutoscalingGroup(w, body)
+	case len(path) == 2 && path[0] == "autoscalingGroups":
+		f.autoscalingGroup(w, r.Method, path[1], body)
+
+	default:
+		writeFakeError(w, http.StatusNotFound, fmt.Sprintf("No route for %s %s", r.Method, r.URL.Path))
+	}
+}
+
+func (f *fakeAPI) getMachine(w http.ResponseWriter, id string) {
+	machine, ok := f.machines[id]
+	if !ok {
+		writeFakeError(w, http.StatusNotFound, "Machine not found")
+		return
+	}
+
+	writeFakeJSON(w, http.StatusOK, machine.fields)
+
+	if len(machine.pending) > 0 {
+		next := machine.pending[0]
+		machine.pending = machine.pending[1:]
+		if next == fakeDeleted {
+			delete(f.machines, id)
+			return
+		}
+		machine.fields["state"] = next
+	}
+}
+
+func (f *fakeAPI) createMachine(w http.ResponseWriter, body map[string]interface{}) {
+	for _, field := range []string{"region", "machineType", "size", "billingType", "machineName", "templateId"} {
+		if v, ok := body[field]; !ok || v == "" {
+			writeFakeError(w, http.StatusBadRequest, fmt.Sprintf("Missing required parameter %s", field))
+			return
+		}
+	}
+
+	id := f.newID("ps")
+	fields := map[string]interface{}{
+		"id":                    id,
+		"name":                  body["machineName"],
+		"os":                    "Ubuntu 18.04.3 LTS; uname: 4.15.0-20-generic",
+		"ram":                   "32212254720",
+		"cpus":                  8,
+		"gpu":                   "Quadro P4000",
+		"storageTotal":          fmt.Sprint(int(body["size"].(float64)) * 1073741824),
+		"storageUsed":           0,
+		"usageRate":             fmt.Sprintf("%s %s", body["machineType"], body["billingType"]),
+		"shutdownTimeoutForces": false,
+		"performAutoSnapshot":   false,
+		"autoSnapshotFrequency": nil,
+		"autoSnapshotSaveCount": nil,
+		"agentType":             "LinuxHeadless",
+		"dtCreated":             time.Now().UTC().Format(time.RFC3339),
+		"state":                 "provisioning",
+		"networkId":             "nabc123",
+		"privateIpAddress":      fmt.Sprintf("10.64.0.%d", f.lastID),
+		"publicIpAddress":       nil,
+		"region":                body["region"],
+		"userId":                fakeUserID,
+		"teamId":                "te1001",
+		"scriptId":              nil,
+		"dtLastRun":             nil,
+		"isManaged":             false,
+	}
+	for _, field := range []string{"networkId", "userId", "teamId", "scriptId", "isManaged", "performAutoSnapshot", "autoSnapshotFrequency", "autoSnapshotSaveCount"} {
+		if v, ok := body[field]; ok {
+			fields[field] = v
+		}
+	}
+	if body["assignPublicIp"] == true {
+		fields["publicIpAddress"] = fmt.Sprintf("203.0.113.%d", f.lastID)
+	}
+	// the API defaults to shutting machines down after a day; an explicit
+	// null keeps them running forever
+	fields["shutdownTimeoutInHours"] = 24
+	if v, ok := body["shutdownTimeoutInHours"]; ok {
+		fields["shutdownTimeoutInHours"] = v
+	}
+
+	f.machines[id] = &fakeMachine{fields: fields, pending: []string{"ready"}}
+
+	writeFakeJSON(w, http.StatusOK, fields)
+}
+
+func (f *fakeAPI) machineAction(w http.ResponseWriter, id, action string, body map[string]interface{}) {
+	machine, ok := f.machines[id]
+	if !ok {
+		writeFakeError(w, http.StatusNotFound, "Machine not found")
+		return
+	}
+	state := machine.fields["state"]
+
+	switch action {
+	case "updateMachine":
+		fieldNames := map[string]string{"machineName": "name"}
+		for k, v := range body {
+			field, ok := fieldNames[k]
+			if !ok {
+				field = k
+			}
+			machine.fields[field] = v
+		}
+	case "start":
+		if state != "off" {
+			writeFakeError(w, http.StatusBadRequest, fmt.Sprintf("Cannot start machine in state %s", state))
+			return
+		}
+		machine.fields["state"] = "starting"
+		machine.pending = []string{"ready"}
+	case "stop":
+		if state != "ready" {
+			writeFakeError(w, http.StatusBadRequest, fmt.Sprintf("Cannot stop machine in state %s", state))
+			return
+		}
+		machine.fields["state"] = "stopping"
+		machine.pending = []string{"off"}
+	case "destroyMachine":
+		machine.fields["state"] = "deprovisioning"
+		machine.pending = []string{fakeDeleted}
+	default:
+		writeFakeError(w, http.StatusNotFound, fmt.Sprintf("No such machine action %s", action))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (f *fakeAPI) createScript(w http.ResponseWriter, body map[string]interface{}) {
+	if body["scriptName"] == nil || body["scriptText"] == nil {
+		writeFakeError(w, http.StatusBadRequest, "Missing required parameter scriptName or scriptText")
+		return
+	}
+
+	id := f.newID("sc")
+	fields := map[string]interface{}{
+		"id":          id,
+		"name":        body["scriptName"],
+		"description": body["scriptDescription"],
+		"ownerType":   "team",
+		"ownerId":     "te1001",
+		"dtCreated":   time.Now().UTC().Format(time.RFC3339),
+		"isEnabled":   true,
+		"runOnce":     false,
+	}
+	for apiField, field := range map[string]string{"isEnabled": "isEnabled", "runOnce": "runOnce"} {
+		if v, ok := body[apiField]; ok {
+			fields[field] = v
+		}
+	}
+
+	f.scripts[id] = &fakeScript{fields: fields, text: body["scriptText"].(string)}
+
+	writeFakeJSON(w, http.StatusOK, fields)
+}
+
+// createTeamNetwork queues the network; like the real API, it only shows up
+// in the team's networks on a later read.
+func (f *fakeAPI) createTeamNetwork(w http.ResponseWriter, team string, body map[string]interface{}) {
+	teamID, err := strconv.Atoi(team)
+	if err != nil || teamID != fakeTeamID {
+		writeFakeError(w, http.StatusNotFound, "Team not found")
+		return
+	}
+	if body["name"] == nil || body["regionId"] == nil {
+		writeFakeError(w, http.StatusBadRequest, "Missing required parameter name or regionId")
+		return
+	}
+
+	f.lastID++
+	namedNetwork := map[string]interface{}{
+		"name": body["name"],
+		"network": map[string]interface{}{
+			"id":      f.lastID,
+			"handle":  fmt.Sprintf("nhandle%d", f.lastID),
+			"isTaken": false,
+			"network": fmt.Sprintf("10.%d.0.0", 100+f.lastID),
+			"netmask": "255.255.255.0",
+			"vlanId":  f.lastID,
+		},
+		"regionId": body["regionId"],
+	}
+	f.pendingNetworks[teamID] = append(f.pendingNetworks[teamID], namedNetwork)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (f *fakeAPI) getTeamNetworks(w http.ResponseWriter, team string) {
+	teamID, _ := strconv.Atoi(team)
+	if teamID != fakeTeamID {
+		writeFakeError(w, http.StatusNotFound, "Team not found")
+		return
+	}
+
+	networks := f.teamNetworks[teamID]
+	if networks == nil {
+		networks = []map[string]interface{}{}
+	}
+	writeFakeJSON(w, http.StatusOK, networks)
+
+	f.teamNetworks[teamID] = append(f.teamNetworks[teamID], f.pendingNetworks[teamID]...)
+	delete(f.pendingNetworks, teamID)
+}
+
+func (f *fakeAPI) deleteTeamNetwork(w http.ResponseWriter, id string) {
+	for teamID, networks := range f.teamNetworks {
+		for i, namedNetwork := range networks {
+			if fmt.Sprint(namedNetwork["network"].(map[string]interface{})["id"]) == id {
+				f.teamNetworks[teamID] = append(networks[:i], networks[i+1:]...)
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+		}
+	}
+
+	writeFakeError(w, http.StatusNotFound, "Network not found")
+}
+
+func (f *fakeAPI) createAutoscalingGroup(w http.ResponseWriter, body map[string]interface{}) {
+	for _, field := range []string{"name", "clusterId", "machineType", "templateId", "networkId"} {
+		if v, ok := body[field]; !ok || v == "" {
+			writeFakeError(w, http.StatusBadRequest, fmt.Sprintf("Missing required parameter %s", field))
+			return
+		}
+	}
+
+	id := f.newID("asg")
+	group := map[string]interface{}{"id": id, "current": 0, "nodes": []interface{}{}}
+	for k, v := range body {
+		group[k] = v
+	}
+	f.autoscalingGroups[id] = group
+
+	writeFakeJSON(w, http.StatusOK, group)
+}
+
+func (f *fakeAPI) autoscalingGroup(w http.ResponseWriter, method, id string, body map[string]interface{}) {
+	group, ok := f.autoscalingGroups[id]
+	if !ok {
+		writeFakeError(w, http.StatusNotFound, "Autoscaling group not found")
+		return
+	}
+
+	switch method {
+	case "GET":
+		writeFakeJSON(w, http.StatusOK, group)
+	case "PATCH":
+		attributes, _ := body["attributes"].(map[string]interface{})
+		for k, v := range attributes {
+			group[k] = v
+		}
+		w.WriteHeader(http.StatusNoContent)
+	case "DELETE":
+		delete(f.autoscalingGroups, id)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeFakeError(w, http.StatusMethodNotAllowed, fmt.Sprintf("Method %s not allowed", method))
+	}
+}
+
+// filterFakeItems keeps the items whose fields equal every query parameter,
+// the way the list endpoints filter.
+func filterFakeItems(items []map[string]interface{}, query map[string][]string) []map[string]interface{} {
+	filtered := []map[string]interface{}{}
+	for _, item := range items {
+		matches := true
+		for k, v := range query {
+			if fmt.Sprint(item[k]) != v[0] {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			filtered = append(filtered, item)
+		}
+	}
+
+	sort.SliceStable(filtered, func(i, j int) bool {
+		return fmt.Sprint(filtered[i]["id"]) < fmt.Sprint(filtered[j]["id"])
+	})
+
+	return filtered
+}
+
+func writeFakeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeFakeError(w http.ResponseWriter, status int, message string) {
+	writeFakeJSON(w, status, map[string]interface{}{
+		"error": map[string]interface{}{
+			"name":    http.StatusText(status),
+			"message": message,
+			"status":  status,
+		},
+	})
+}
@@ -0,0 +1,569 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/crc32"
+	"log"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// lookupAttribute maps a data source attribute to the API field it is
+// filtered on and read back from.
+type lookupAttribute struct {
+	Name     string
+	APIField string
+	// Type defaults to types.StringType.
+	Type attr.Type
+	// NoFilter marks attributes that are only read back, never sent as a
+	// query parameter.
+	NoFilter bool
+}
+
+func (a lookupAttribute) valueType() attr.Type {
+	if a.Type == nil {
+		return types.StringType
+	}
+
+	return a.Type
+}
+
+func (a lookupAttribute) schema(optional, computed bool) schema.Attribute {
+	switch a.valueType() {
+	case types.Int64Type:
+		return schema.Int64Attribute{Optional: optional, Computed: computed}
+	case types.Float64Type:
+		return schema.Float64Attribute{Optional: optional, Computed: computed}
+	case types.BoolType:
+		return schema.BoolAttribute{Optional: optional, Computed: computed}
+	}
+
+	return schema.StringAttribute{Optional: optional, Computed: computed}
+}
+
+// lookup describes a list endpoint that data sources query by filtering on
+// their attributes. Adding a filter is a matter of adding an attribute.
+type lookup struct {
+	// Kind names the object in error messages, e.g. "template".
+	Kind string
+	// Path is the list endpoint relative to the API host.
+	Path       string
+	Attributes []lookupAttribute
+	// fetch lists the objects through a typed PaperspaceClient method;
+	// lookups without one decode the endpoint at Path as plain objects.
+	fetch func(ctx context.Context, paperspaceClient PaperspaceClient, query url.Values) (interface{}, error)
+}
+
+// Schema returns the data source schema: every attribute is an optional
+// filter that is also filled in from the matching object.
+func (l lookup) Schema() map[string]schema.Attribute {
+	s := make(map[string]schema.Attribute, len(l.Attributes))
+	for _, attribute := range l.Attributes {
+		s[attribute.Name] = attribute.schema(!attribute.NoFilter, true)
+	}
+
+	return s
+}
+
+// ListSchema returns the schema of a plural data source: every filter at the
+// top level, the matching objects under key and their ids under "ids".
+func (l lookup) ListSchema(key string) map[string]schema.Attribute {
+	s := map[string]schema.Attribute{
+		"id": schema.StringAttribute{
+			Computed: true,
+		},
+		key: schema.ListNestedAttribute{
+			Computed: true,
+			NestedObject: schema.NestedAttributeObject{
+				Attributes: l.computedSchema(),
+			},
+		},
+		"ids": schema.ListAttribute{
+			Computed:    true,
+			ElementType: types.StringType,
+		},
+	}
+
+	for _, attribute := range l.Attributes {
+		if attribute.NoFilter || attribute.Name == "id" {
+			continue
+		}
+		s[attribute.Name] = attribute.schema(true, false)
+	}
+
+	return s
+}
+
+func (l lookup) computedSchema() map[string]schema.Attribute {
+	s := make(map[string]schema.Attribute, len(l.Attributes))
+	for _, attribute := range l.Attributes {
+		s[attribute.Name] = attribute.schema(false, true)
+	}
+
+	return s
+}
+
+func (l lookup) attributeTypes() map[string]attr.Type {
+	t := make(map[string]attr.Type, len(l.Attributes))
+	for _, attribute := range l.Attributes {
+		t[attribute.Name] = attribute.valueType()
+	}
+
+	return t
+}
+
+// filterNames lists the attributes that can be used as filters.
+func (l lookup) filterNames() []string {
+	names := make([]string, 0, len(l.Attributes))
+	for _, attribute := range l.Attributes {
+		if !attribute.NoFilter {
+			names = append(names, attribute.Name)
+		}
+	}
+
+	return names
+}
+
+// Query builds the API query from the attributes set in config. Like the
+// filters of the list endpoints, zero values count as unset.
+func (l lookup) Query(ctx context.Context, config tfsdk.Config) (url.Values, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	query := url.Values{}
+	for _, attribute := range l.Attributes {
+		if attribute.NoFilter {
+			continue
+		}
+
+		v, d := configValue(ctx, config, path.Root(attribute.Name), attribute.valueType())
+		diags.Append(d...)
+		if v != nil {
+			query.Set(attribute.APIField, fmt.Sprint(v))
+		}
+	}
+
+	return query, diags
+}
+
+// configValue returns the Go value of the attribute at p, or nil when it is
+// null, unknown or the zero value.
+func configValue(ctx context.Context, config tfsdk.Config, p path.Path, t attr.Type) (interface{}, diag.Diagnostics) {
+	var target attr.Value
+	var diags diag.Diagnostics
+
+	switch t {
+	case types.Int64Type:
+		var v types.Int64
+		diags = config.GetAttribute(ctx, p, &v)
+		target = v
+	case types.Float64Type:
+		var v types.Float64
+		diags = config.GetAttribute(ctx, p, &v)
+		target = v
+	case types.BoolType:
+		var v types.Bool
+		diags = config.GetAttribute(ctx, p, &v)
+		target = v
+	default:
+		var v types.String
+		diags = config.GetAttribute(ctx, p, &v)
+		target = v
+	}
+
+	m := make(MapIf)
+	m.AppendIfSet("v", target)
+
+	return m["v"], diags
+}
+
+// List returns every object matching query.
+func (l lookup) List(ctx context.Context, paperspaceClient PaperspaceClient, query url.Values) ([]map[string]interface{}, error) {
+	var items []map[string]interface{}
+	var err error
+
+	if l.fetch != nil {
+		var objects interface{}
+		if objects, err = l.fetch(ctx, paperspaceClient, query); err == nil {
+			items, err = lookupItems(objects)
+		}
+	} else {
+		url := fmt.Sprintf("%s%s?%s", paperspaceClient.APIHost, l.Path, query.Encode())
+		_, err = paperspaceClient.RequestInterface(ctx, "GET", url, nil, &items)
+	}
+	if err != nil {
+		if IsNotFound(err) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("Error reading paperspace %s: %w", l.Kind, err)
+	}
+
+	return items, nil
+}
+
+// lookupItems turns the typed objects returned by a fetch into the API
+// field maps attributes are read from, keyed by their JSON names.
+func lookupItems(objects interface{}) ([]map[string]interface{}, error) {
+	data, err := json.Marshal(objects)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []map[string]interface{}
+	if err := json.Unmarshal(data, &items); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// ReadOne implements a singular data source: the filters set in config must
+// match exactly one object, whose attributes are then stored in state.
+func (l lookup) ReadOne(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse, m interface{}) {
+	paperspaceClient := newInternalPaperspaceClient(m)
+
+	query, diags := l.Query(ctx, req.Config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if len(query) == 0 {
+		resp.Diagnostics.AddError(fmt.Sprintf("Missing paperspace %s filter", l.Kind),
+			fmt.Sprintf("must specify query filter properties: set at least one of %s", strings.Join(l.filterNames(), ", ")))
+		return
+	}
+
+	items, err := l.List(ctx, paperspaceClient, query)
+	if err != nil {
+		resp.Diagnostics.Append(errorDiagnostic(fmt.Sprintf("Error reading paperspace %s", l.Kind), err))
+		return
+	}
+
+	item, err := l.one(items, query.Encode())
+	if err != nil {
+		resp.Diagnostics.AddError(fmt.Sprintf("Error reading paperspace %s", l.Kind), err.Error())
+		return
+	}
+
+	resp.State.Raw = req.Config.Raw.Copy()
+	resp.Diagnostics.Append(l.Set(ctx, &resp.State, item)...)
+}
+
+// ReadList implements a plural data source: every object matching the
+// filters set in config (or every object, without filters) is stored under
+// key.
+func (l lookup) ReadList(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse, m interface{}, key string) {
+	paperspaceClient := newInternalPaperspaceClient(m)
+
+	query, diags := l.Query(ctx, req.Config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	items, err := l.List(ctx, paperspaceClient, query)
+	if err != nil {
+		resp.Diagnostics.Append(errorDiagnostic(fmt.Sprintf("Error reading paperspace %ss", l.Kind), err))
+		return
+	}
+
+	resp.State.Raw = req.Config.Raw.Copy()
+	resp.Diagnostics.Append(l.SetList(ctx, &resp.State, key, query.Encode(), items)...)
+}
+
+// SetList stores items under key and their ids under "ids". The data source
+// id is derived from filters, which should describe every filter applied.
+func (l lookup) SetList(ctx context.Context, state *tfsdk.State, key, filters string, items []map[string]interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	attributeTypes := l.attributeTypes()
+	list := make([]attr.Value, 0, len(items))
+	ids := make([]attr.Value, 0, len(items))
+	for i, item := range items {
+		values, d := l.Flatten(item, path.Root(key).AtListIndex(i))
+		diags.Append(d...)
+		if diags.HasError() {
+			return diags
+		}
+		for name, t := range attributeTypes {
+			if _, ok := values[name]; !ok {
+				values[name] = nullValue(t)
+			}
+		}
+
+		object, d := types.ObjectValue(attributeTypes, values)
+		diags.Append(d...)
+		if diags.HasError() {
+			return diags
+		}
+
+		list = append(list, object)
+		ids = append(ids, values["id"])
+	}
+
+	log.Printf("[INFO] paperspace %s lookup found %d matches", l.Kind, len(list))
+
+	listValue, d := types.ListValue(types.ObjectType{AttrTypes: attributeTypes}, list)
+	diags.Append(d...)
+	idsValue, d := types.ListValue(types.StringType, ids)
+	diags.Append(d...)
+	if diags.HasError() {
+		return diags
+	}
+
+	diags.Append(state.SetAttribute(ctx, path.Root(key), listValue)...)
+	diags.Append(state.SetAttribute(ctx, path.Root("ids"), idsValue)...)
+	diags.Append(state.SetAttribute(ctx, path.Root("id"), strconv.Itoa(hashcodeString(l.Path+"?"+filters)))...)
+
+	return diags
+}
+
+// one returns the single item in items. filters describes the filters that
+// selected them, for the error message.
+func (l lookup) one(items []map[string]interface{}, filters string) (map[string]interface{}, error) {
+	if len(items) > 1 {
+		return nil, fmt.Errorf("found more than one %s matching given properties (%s); %d matched, add filters to narrow the search down", l.Kind, filters, len(items))
+	}
+	if len(items) == 0 {
+		return nil, fmt.Errorf("no %s found matching given properties (%s)", l.Kind, filters)
+	}
+
+	return items[0], nil
+}
+
+// Set stores item's attributes, id included, in state.
+func (l lookup) Set(ctx context.Context, state *tfsdk.State, item map[string]interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	values, diags := l.Flatten(item, path.Empty())
+	if diags.HasError() {
+		return diags
+	}
+
+	id, _ := values["id"].(types.String)
+	if id.ValueString() == "" {
+		diags.AddError(fmt.Sprintf("Error unmarshalling paperspace %s read response", l.Kind), fmt.Sprintf("no %s id found for %s", l.Kind, l.Kind))
+		return diags
+	}
+
+	log.Printf("[INFO] paperspace %s lookup found id: %v", l.Kind, id.ValueString())
+
+	for name, value := range values {
+		diags.Append(state.SetAttribute(ctx, path.Root(name), value)...)
+	}
+
+	return diags
+}
+
+// hashcodeString hashes s to a non-negative int, matching the ids earlier
+// versions of the plural data sources stored.
+func hashcodeString(s string) int {
+	v := int(crc32.ChecksumIEEE([]byte(s)))
+	if v >= 0 {
+		return v
+	}
+	if -v >= 0 {
+		return -v
+	}
+
+	return 0
+}
+
+// filterByRegex keeps the items whose field matches re.
+func filterByRegex(items []map[string]interface{}, field string, re *regexp.Regexp) []map[string]interface{} {
+	filtered := make([]map[string]interface{}, 0, len(items))
+	for _, item := range items {
+		if v, ok := item[field].(string); ok && re.MatchString(v) {
+			filtered = append(filtered, item)
+		}
+	}
+
+	return filtered
+}
+
+// mostRecent returns the item with the latest timestamp in field, or nil
+// when items is empty. Timestamps that do not parse as RFC 3339 are
+// compared as strings.
+func mostRecent(items []map[string]interface{}, field string) map[string]interface{} {
+	if len(items) == 0 {
+		return nil
+	}
+
+	sorted := make([]map[string]interface{}, len(items))
+	copy(sorted, items)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, _ := sorted[i][field].(string)
+		b, _ := sorted[j][field].(string)
+
+		ta, errA := time.Parse(time.RFC3339, a)
+		tb, errB := time.Parse(time.RFC3339, b)
+		if errA == nil && errB == nil {
+			return ta.After(tb)
+		}
+
+		return a > b
+	})
+
+	return sorted[0]
+}
+
+// Flatten converts an API object into attribute values of the right types.
+// Fields missing from the object or null are left out. Conversion errors
+// point at the attribute under p.
+func (l lookup) Flatten(item map[string]interface{}, p path.Path) (map[string]attr.Value, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	values := make(map[string]attr.Value, len(l.Attributes))
+	for _, attribute := range l.Attributes {
+		v, ok := item[attribute.APIField]
+		if !ok || v == nil {
+			continue
+		}
+
+		value, err := convertLookupValue(v, attribute.valueType())
+		if err != nil {
+			diags.AddAttributeError(p.AtName(attribute.Name), fmt.Sprintf("Error reading paperspace %s", l.Kind),
+				fmt.Sprintf("Unexpected value for API field %s: %s", attribute.APIField, err))
+			continue
+		}
+		values[attribute.Name] = value
+	}
+
+	return values, diags
+}
+
+func nullValue(t attr.Type) attr.Value {
+	switch t {
+	case types.Int64Type:
+		return types.Int64Null()
+	case types.Float64Type:
+		return types.Float64Null()
+	case types.BoolType:
+		return types.BoolNull()
+	}
+
+	return types.StringNull()
+}
+
+// convertLookupValue converts a decoded JSON value to a value of type t,
+// accepting numbers and booleans sent as strings and the other way around.
+func convertLookupValue(v interface{}, t attr.Type) (attr.Value, error) {
+	switch t {
+	case types.StringType:
+		switch value := v.(type) {
+		case string:
+			return types.StringValue(value), nil
+		case float64:
+			return types.StringValue(strconv.FormatFloat(value, 'f', -1, 64)), nil
+		case bool:
+			return types.StringValue(strconv.FormatBool(value)), nil
+		}
+	case types.Int64Type:
+		switch value := v.(type) {
+		case float64:
+			return types.Int64Value(int64(value)), nil
+		case string:
+			i, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return nil, err
+			}
+			return types.Int64Value(i), nil
+		}
+	case types.Float64Type:
+		switch value := v.(type) {
+		case float64:
+			return types.Float64Value(value), nil
+		case string:
+			f, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return nil, err
+			}
+			return types.Float64Value(f), nil
+		}
+	case types.BoolType:
+		switch value := v.(type) {
+		case bool:
+			return types.BoolValue(value), nil
+		case string:
+			b, err := strconv.ParseBool(value)
+			if err != nil {
+				return nil, err
+			}
+			return types.BoolValue(b), nil
+		}
+	}
+
+	return nil, fmt.Errorf("cannot convert %T to %s", v, t)
+}
+
+// lookupDataSource is a data source backed entirely by a lookup: singular
+// when key is empty, otherwise plural with the matches stored under key.
+type lookupDataSource struct {
+	typeName string
+	lookup   lookup
+	key      string
+	meta     interface{}
+}
+
+func (d *lookupDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + d.typeName
+}
+
+func (d *lookupDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	d.meta = req.ProviderData
+}
+
+func (d *lookupDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	if d.key == "" {
+		resp.Schema = schema.Schema{Attributes: d.lookup.Schema()}
+		return
+	}
+
+	resp.Schema = schema.Schema{Attributes: d.lookup.ListSchema(d.key)}
+}
+
+func (d *lookupDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	if d.key == "" {
+		d.lookup.ReadOne(ctx, req, resp, d.meta)
+		return
+	}
+
+	d.lookup.ReadList(ctx, req, resp, d.meta, d.key)
+}
+
+// regexpValidator checks that a string attribute compiles as a regular
+// expression.
+type regexpValidator struct{}
+
+func (v regexpValidator) Description(ctx context.Context) string {
+	return "value must be a valid regular expression"
+}
+
+func (v regexpValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v regexpValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if _, err := regexp.Compile(req.ConfigValue.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid regular expression", err.Error())
+	}
+}
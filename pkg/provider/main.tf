@@ -1,12 +1,37 @@
 provider "paperspace" {
   region = "East Coast (NY2)"
   api_key = "1be4f97..." // modify this to use your actual api key
+  # max_retries = 3 // optional, how often transient API errors (429, 502-504) are retried
+  # retry_max_wait = 30 // optional, maximum seconds to wait between retries
+  # requests_per_second = 5 // optional, client-side API rate limit shared by all resources, 0 disables it
+  # request_timeout = 30 // optional, seconds allowed for each API request attempt
+  # http_proxy = "http://proxy.mycompany.com:3128" // optional, route API calls through an egress proxy
+  # ca_cert_file = "/etc/ssl/mycompany-ca.pem" // optional, extra CA certificates to trust, e.g. for a TLS-intercepting proxy
 }
 
 data "paperspace_template" "my-template-1" {
   id = "t04azgph" // this is one of the Ubuntu Server 18.04 templates
 }
 
+# track the newest nightly image instead of pinning an id:
+# data "paperspace_template" "latest-ml-base" {
+#   name_regex  = "^ml-base-\\d{4}-\\d{2}-\\d{2}$"
+#   most_recent = true
+# }
+
+# plural data sources return every match, e.g. for use with for_each:
+# data "paperspace_templates" "ubuntu" {
+#   label = "Ubuntu 18.04 Server"
+# }
+# (data.paperspace_templates.ubuntu.templates / .ids; see also paperspace_machines, paperspace_networks, paperspace_users)
+
+# machine types offered in a region, with cpus, ram, gpu, hourly_price and available:
+# data "paperspace_machine_types" "gpus" {
+#   region    = "East Coast (NY2)"
+#   available = true
+# }
+# (machine_type on paperspace_machine is checked against this catalog at plan time)
+
 data "paperspace_user" "my-user-1" {
   email = "me@mycompany.com" // change to the email address of a user on your paperspace team
   team_id = "te1234567"
@@ -21,12 +46,15 @@ echo "Hello, World" > index.html
 ufw allow 8080
 nohup busybox httpd -f -p 8080 &
 EOF
+  # or, instead of script_text, upload a file rendered with text/template ({{ .name }}) placeholders:
+  # script_file = "${path.module}/startup.sh"
+  # script_vars = { name = "value" } // optional, leave out to upload the file as is
   is_enabled = true
   run_once = false
 }
 
 resource "paperspace_machine" "my-machine-1" {
-  region = "East Coast (NY2)" // optional, defaults to provider region if not specified
+  region = "East Coast (NY2)" // optional, defaults to provider region if not specified; codes like "ny2" work too
   name = "Terraform Test"
   machine_type = "C1"
   size = 50
@@ -37,9 +65,22 @@ resource "paperspace_machine" "my-machine-1" {
   team_id = data.paperspace_user.my-user-1.team_id
   script_id = paperspace_script.my-script-1.id // optional, remove for no script
   shutdown_timeout_in_hours = 42
-  # live_forever = true # enable this to make the machine have no shutdown timeout
+  # live_forever = true # enable this (and remove shutdown_timeout_in_hours) to make the machine have no shutdown timeout
+  # power_state = "off" # set to "off" or "running" to stop or start the machine
 }
 
+# attach a script owned by another stack, looked up by id or name:
+# data "paperspace_script" "shared" {
+#   name = "shared-startup-script"
+# }
+# resource "paperspace_machine_script_attachment" "shared" {
+#   machine_id = paperspace_machine.my-machine-1.id
+#   script_id  = data.paperspace_script.shared.id
+# }
+# (leave script_id unset on the machine; destroying the attachment detaches
+# the script)
+
 resource "paperspace_network" "network" {
   team_id = 00000 // change to your team's actual database id (unlike team_id everywhere else, which is your team handle)
+  # region = "ams1" // optional, defaults to provider region; data.paperspace_regions lists every code and name
 }
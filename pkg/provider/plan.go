@@ -0,0 +1,57 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// suppressEquivalentPlan undoes the update Terraform plans when every
+// configuration change was suppressed by a plan modifier, e.g. a region
+// spelled differently. By the time the plan modifiers put prior values back,
+// the framework has already marked every computed attribute unknown, so the
+// plan is replaced with the prior state when nothing else changes.
+func suppressEquivalentPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.State.Raw.IsNull() || resp.Plan.Raw.IsNull() || resp.Plan.Raw.Equal(req.State.Raw) {
+		return
+	}
+
+	var plan, state, config map[string]tftypes.Value
+	if resp.Plan.Raw.As(&plan) != nil || req.State.Raw.As(&state) != nil || req.Config.Raw.As(&config) != nil {
+		return
+	}
+
+	for name, planned := range plan {
+		if planned.Equal(state[name]) {
+			continue
+		}
+		if !planned.IsKnown() && config[name].IsNull() {
+			continue
+		}
+
+		return
+	}
+
+	resp.Plan.Raw = req.State.Raw.Copy()
+}
+
+// stringRequiresReplaceUnlessUnset forces a new resource when an argument
+// the API cannot update changes. Null and "" both mean unset, as older
+// releases stored unset arguments as "".
+func stringRequiresReplaceUnlessUnset() planmodifier.String {
+	return stringplanmodifier.RequiresReplaceIf(func(ctx context.Context, req planmodifier.StringRequest, resp *stringplanmodifier.RequiresReplaceIfFuncResponse) {
+		resp.RequiresReplace = req.PlanValue.IsUnknown() || req.PlanValue.ValueString() != req.StateValue.ValueString()
+	}, "Changing this value requires a new resource.", "Changing this value requires a new resource.")
+}
+
+// boolRequiresReplaceUnlessUnset is stringRequiresReplaceUnlessUnset for
+// bools, where null and false both mean unset.
+func boolRequiresReplaceUnlessUnset() planmodifier.Bool {
+	return boolplanmodifier.RequiresReplaceIf(func(ctx context.Context, req planmodifier.BoolRequest, resp *boolplanmodifier.RequiresReplaceIfFuncResponse) {
+		resp.RequiresReplace = req.PlanValue.IsUnknown() || req.PlanValue.ValueBool() != req.StateValue.ValueBool()
+	}, "Changing this value requires a new resource.", "Changing this value requires a new resource.")
+}
@@ -0,0 +1,76 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	pollMinInterval = 500 * time.Millisecond
+	pollMaxInterval = 10 * time.Second
+)
+
+// retryError tells retryContext whether to keep calling the function.
+type retryError struct {
+	err       error
+	retryable bool
+}
+
+func retryableError(err error) *retryError {
+	return &retryError{err: err, retryable: true}
+}
+
+// nonRetryableError stops retryContext, which returns err. A nil err stops
+// it successfully.
+func nonRetryableError(err error) *retryError {
+	return &retryError{err: err}
+}
+
+// retryContext calls f until it returns nil or a non-retryable error, backing
+// off between calls, and gives up once timeout has passed or ctx is done. f
+// gets a context bounded by the timeout, which its API calls should use so
+// that they are cancelled with the loop.
+func retryContext(ctx context.Context, timeout time.Duration, f func(context.Context) *retryError) error {
+	pollCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	interval := pollMinInterval
+	for {
+		result := f(pollCtx)
+		if result == nil {
+			return nil
+		}
+		if !result.retryable {
+			return result.err
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-pollCtx.Done():
+			timer.Stop()
+			return stoppedError(ctx, timeout, result.err)
+		case <-timer.C:
+		}
+
+		interval *= 2
+		if interval > pollMaxInterval {
+			interval = pollMaxInterval
+		}
+	}
+}
+
+// stoppedError explains why retryContext gave up: either the caller's context
+// was cancelled, e.g. when Terraform is interrupted, or the timeout passed.
+func stoppedError(ctx context.Context, timeout time.Duration, err error) error {
+	reason := fmt.Sprintf("timeout after %s", timeout)
+	if ctx.Err() != nil {
+		reason = fmt.Sprintf("cancelled: %s", ctx.Err())
+	}
+
+	if err == nil {
+		return errors.New(reason)
+	}
+	return fmt.Errorf("%s: %w", reason, err)
+}
@@ -0,0 +1,48 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRetryContext_cancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	calls := 0
+	start := time.Now()
+	err := retryContext(ctx, time.Minute, func(ctx context.Context) *retryError {
+		calls++
+		if calls == 2 {
+			cancel()
+		}
+		return retryableError(errors.New("machine not ready"))
+	})
+
+	if err == nil || !strings.Contains(err.Error(), "cancelled") {
+		t.Fatalf("expected a cancelled error, got %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("expected polling to stop after 2 calls, got %d", calls)
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("expected polling to stop promptly, took %s", elapsed)
+	}
+}
+
+func TestPaperspaceClient_cancel(t *testing.T) {
+	api := newFakeAPI(t)
+	config := ClientConfig{APIKey: fakeAPIKey, APIHost: api.URL}
+	paperspaceClient := config.Client()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := paperspaceClient.GetMachine(ctx, "psnotreached")
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
@@ -1,92 +1,203 @@
 package provider
 
 import (
+	"context"
 	"log"
+	"net/http"
 	"os"
+	"time"
 
 	"github.com/Paperspace/paperspace-go"
-	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-framework-validators/float64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
-func Provider() *schema.Provider {
-	return &schema.Provider{
-		Schema: map[string]*schema.Schema{
-			"api_key": &schema.Schema{
-				Type:        schema.TypeString,
-				Optional:    true,
-				DefaultFunc: envDefaultFunc("PAPERSPACE_API_KEY"),
+const defaultAPIHost = "https://api.paperspace.io"
+
+type paperspaceProvider struct {
+	version string
+
+	// wrapTransport, if set, wraps the shared HTTP transport. Tests use it
+	// to record and replay API traffic.
+	wrapTransport func(http.RoundTripper) http.RoundTripper
+}
+
+type providerModel struct {
+	APIKey             types.String  `tfsdk:"api_key"`
+	APIHost            types.String  `tfsdk:"api_host"`
+	Region             types.String  `tfsdk:"region"`
+	MaxRetries         types.Int64   `tfsdk:"max_retries"`
+	RetryMaxWait       types.Int64   `tfsdk:"retry_max_wait"`
+	RequestTimeout     types.Int64   `tfsdk:"request_timeout"`
+	HTTPProxy          types.String  `tfsdk:"http_proxy"`
+	CACertFile         types.String  `tfsdk:"ca_cert_file"`
+	InsecureSkipVerify types.Bool    `tfsdk:"insecure_skip_verify"`
+	RequestsPerSecond  types.Float64 `tfsdk:"requests_per_second"`
+}
+
+// New returns the provider factory served by main.
+func New(version string) func() provider.Provider {
+	return func() provider.Provider {
+		return &paperspaceProvider{version: version}
+	}
+}
+
+func (p *paperspaceProvider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
+	resp.TypeName = "paperspace"
+	resp.Version = p.version
+}
+
+func (p *paperspaceProvider) Schema(ctx context.Context, req provider.SchemaRequest, resp *provider.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"api_key": schema.StringAttribute{
+				Optional:  true,
+				Sensitive: true,
 			},
-			"api_host": &schema.Schema{
-				Type:        schema.TypeString,
-				Optional:    true,
-				DefaultFunc: envDefaultFuncAllowMissingDefault("PAPERSPACE_API_HOST", "https://api.paperspace.io"),
+			"api_host": schema.StringAttribute{
+				Optional: true,
 			},
-			"region": &schema.Schema{
-				Type:        schema.TypeString,
-				Optional:    true,
-				DefaultFunc: envDefaultFuncAllowMissing("PAPERSPACE_REGION"),
+			"region": schema.StringAttribute{
+				Optional:   true,
+				Validators: []validator.String{regionValidator{}},
+			},
+			"max_retries": schema.Int64Attribute{
+				Optional:   true,
+				Validators: []validator.Int64{int64validator.AtLeast(0)},
+			},
+			"retry_max_wait": schema.Int64Attribute{
+				Optional:   true,
+				Validators: []validator.Int64{int64validator.AtLeast(1)},
+			},
+			"request_timeout": schema.Int64Attribute{
+				Optional:   true,
+				Validators: []validator.Int64{int64validator.AtLeast(1)},
+			},
+			"http_proxy": schema.StringAttribute{
+				Optional: true,
+			},
+			"ca_cert_file": schema.StringAttribute{
+				Optional: true,
+			},
+			"insecure_skip_verify": schema.BoolAttribute{
+				Optional: true,
+			},
+			"requests_per_second": schema.Float64Attribute{
+				Optional:   true,
+				Validators: []validator.Float64{float64validator.AtLeast(0)},
 			},
 		},
+	}
+}
 
-		ResourcesMap: map[string]*schema.Resource{
-			"paperspace_autoscaling_group": resourceAutoscalingGroup(),
-			"paperspace_machine":           resourceMachine(),
-			"paperspace_network":           resourceNetwork(),
-			"paperspace_script":            resourceScript(),
-		},
+func (p *paperspaceProvider) Configure(ctx context.Context, req provider.ConfigureRequest, resp *provider.ConfigureResponse) {
+	var data providerModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-		DataSourcesMap: map[string]*schema.Resource{
-			"paperspace_job_storage": dataSourceJobStorage(),
-			"paperspace_network":     dataSourceNetwork(),
-			"paperspace_template":    dataSourceTemplate(),
-			"paperspace_user":        dataSourceUser(),
+	config := ClientConfig{
+		APIKey:  stringFromConfig(data.APIKey, "PAPERSPACE_API_KEY", ""),
+		APIHost: stringFromConfig(data.APIHost, "PAPERSPACE_API_HOST", defaultAPIHost),
+		Region:  regionName(stringFromConfig(data.Region, "PAPERSPACE_REGION", "")),
+
+		MaxRetries:   int(int64FromConfig(data.MaxRetries, defaultMaxRetries)),
+		RetryMaxWait: time.Duration(int64FromConfig(data.RetryMaxWait, int64(defaultRetryMaxWait/time.Second))) * time.Second,
+
+		RequestTimeout: time.Duration(int64FromConfig(data.RequestTimeout, int64(defaultAttemptTimeout/time.Second))) * time.Second,
+		Transport: TransportConfig{
+			HTTPProxy:          stringFromConfig(data.HTTPProxy, "PAPERSPACE_HTTP_PROXY", ""),
+			CACertFile:         stringFromConfig(data.CACertFile, "PAPERSPACE_CA_CERT_FILE", ""),
+			InsecureSkipVerify: data.InsecureSkipVerify.ValueBool(),
 		},
+	}
 
-		ConfigureFunc: providerConfigure,
+	// a single limiter per provider instance, shared by every client built
+	// from this config; 0 disables client-side rate limiting
+	rps := defaultRequestsPerSecond
+	if !data.RequestsPerSecond.IsNull() && !data.RequestsPerSecond.IsUnknown() {
+		rps = data.RequestsPerSecond.ValueFloat64()
+	}
+	if rps > 0 {
+		config.RateLimiter = newRateLimiter(rps)
 	}
-}
 
-func envDefaultFunc(k string) schema.SchemaDefaultFunc {
-	return func() (interface{}, error) {
-		if v := os.Getenv(k); v != "" {
-			return v, nil
-		}
+	config.MachineTypes = newMachineTypeCatalog()
 
-		return nil, nil
+	httpClient, err := config.NewHTTPClient()
+	if err != nil {
+		resp.Diagnostics.AddError("Error configuring paperspace provider", err.Error())
+		return
 	}
-}
+	if p.wrapTransport != nil {
+		httpClient.Transport = p.wrapTransport(httpClient.Transport)
+	}
+	config.HTTPClient = httpClient
 
-func envDefaultFuncAllowMissing(k string) schema.SchemaDefaultFunc {
-	return func() (interface{}, error) {
-		v := os.Getenv(k)
-		return v, nil
+	log.Printf("[INFO] paperspace provider api_host %v", config.APIHost)
+	if config.Region != "" {
+		log.Printf("[INFO] paperspace provider region %v", config.Region)
 	}
+	if config.RateLimiter != nil {
+		log.Printf("[INFO] paperspace provider limiting API calls to %v requests per second", config.RateLimiter.rate)
+	}
+
+	resp.DataSourceData = config
+	resp.ResourceData = config
 }
 
-func envDefaultFuncAllowMissingDefault(k string, d string) schema.SchemaDefaultFunc {
-	return func() (interface{}, error) {
-		if v := os.Getenv(k); v != "" {
-			return v, nil
-		}
+func (p *paperspaceProvider) Resources(ctx context.Context) []func() resource.Resource {
+	return []func() resource.Resource{
+		resourceAutoscalingGroup,
+		resourceMachine,
+		resourceMachineScriptAttachment,
+		resourceNetwork,
+		resourceScript,
+	}
+}
 
-		return d, nil
+func (p *paperspaceProvider) DataSources(ctx context.Context) []func() datasource.DataSource {
+	return []func() datasource.DataSource{
+		dataSourceJobStorage,
+		dataSourceMachineTypes,
+		dataSourceMachines,
+		dataSourceNetwork,
+		dataSourceNetworks,
+		dataSourceRegions,
+		dataSourceScript,
+		dataSourceTemplate,
+		dataSourceTemplates,
+		dataSourceUser,
+		dataSourceUsers,
 	}
 }
 
-func providerConfigure(d *schema.ResourceData) (interface{}, error) {
-	config := ClientConfig{
-		APIKey:  d.Get("api_key").(string),
-		APIHost: d.Get("api_host").(string),
-		Region:  d.Get("region").(string),
+// stringFromConfig returns the configured value, falling back to the
+// environment variable k and then to d.
+func stringFromConfig(v types.String, k string, d string) string {
+	if !v.IsNull() && !v.IsUnknown() {
+		return v.ValueString()
+	}
+	if v := os.Getenv(k); v != "" {
+		return v
 	}
 
-	log.Printf("[INFO] paperspace provider api_key %v", config.APIKey)
-	log.Printf("[INFO] paperspace provider api_host %v", config.APIHost)
-	if config.Region != "" {
-		log.Printf("[INFO] paperspace provider region %v", config.Region)
+	return d
+}
+
+func int64FromConfig(v types.Int64, d int64) int64 {
+	if v.IsNull() || v.IsUnknown() {
+		return d
 	}
 
-	return config, nil
+	return v.ValueInt64()
 }
 
 func newInternalPaperspaceClient(v interface{}) PaperspaceClient {
@@ -109,6 +220,12 @@ func newPaperspaceClient(v interface{}) *paperspace.Client {
 	if config.APIHost != "" {
 		apiBackend.BaseURL = config.APIHost
 	}
+	// the backend's own debug output dumps the x-api-key header; requests
+	// are logged with secrets redacted by loggingTransport instead
+	apiBackend.Debug = false
+	apiBackend.HTTPClient = &http.Client{
+		Transport: &loggingTransport{next: config.httpClient().Transport},
+	}
 
 	client = paperspace.NewClientWithBackend(paperspace.Backend(apiBackend))
 	client.APIKey = config.APIKey
@@ -0,0 +1,263 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func testAccProtoV6ProviderFactories() map[string]func() (tfprotov6.ProviderServer, error) {
+	return map[string]func() (tfprotov6.ProviderServer, error){
+		"paperspace": providerserver.NewProtocol6WithError(New("test")()),
+	}
+}
+
+func TestProvider(t *testing.T) {
+	server, err := providerserver.NewProtocol6WithError(New("test")())()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	resp, err := server.GetProviderSchema(context.Background(), &tfprotov6.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	for _, d := range resp.Diagnostics {
+		if d.Severity == tfprotov6.DiagnosticSeverityError {
+			t.Errorf("err: %s: %s", d.Summary, d.Detail)
+		}
+	}
+}
+
+func TestProvider_retryValidation(t *testing.T) {
+	cases := []struct {
+		name      string
+		attribute string
+		value     string
+		error     string
+	}{
+		{name: "negative max_retries", attribute: "max_retries", value: "-1", error: `max_retries value must be at least 0`},
+		{name: "zero retry_max_wait", attribute: "retry_max_wait", value: "0", error: `retry_max_wait value must be at least 1`},
+		{name: "negative retry_max_wait", attribute: "retry_max_wait", value: "-5", error: `retry_max_wait value must be at least 1`},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			resource.UnitTest(t, resource.TestCase{
+				ProtoV6ProviderFactories: testAccProtoV6ProviderFactories(),
+				Steps: []resource.TestStep{
+					{
+						Config: fmt.Sprintf(`
+provider "paperspace" {
+  api_key = "test"
+  %s = %s
+}
+
+data "paperspace_regions" "all" {}
+`, tc.attribute, tc.value),
+						ExpectError: regexp.MustCompile(tc.error),
+					},
+				},
+			})
+		})
+	}
+}
+
+// TestProvider_upgradeState checks that state written by the SDK based
+// releases loads, with the empty strings and false values the SDK stored for
+// unset optional attributes read as null and numbers stored as strings
+// converted.
+func TestProvider_upgradeState(t *testing.T) {
+	cases := []struct {
+		typeName string
+		version  int64
+		rawState string
+		expected map[string]tftypes.Value
+	}{
+		{
+			typeName: "paperspace_network",
+			rawState: `{"id":"42","team_id":1001,"handle":"nh1","is_taken":false,"netmask":"255.255.255.0","network":"10.64.0.0","name":"managed_network_abc1234","timeouts":null}`,
+			expected: map[string]tftypes.Value{
+				"id":      tftypes.NewValue(tftypes.String, "42"),
+				"team_id": tftypes.NewValue(tftypes.Number, big.NewFloat(1001)),
+			},
+		},
+		{
+			typeName: "paperspace_machine",
+			rawState: `{"id":"psabc123","region":"East Coast (NY2)","machine_type":"C2","size":50,"billing_type":"hourly","name":"tf-machine","template_id":"tubuntu1","assign_public_ip":true,"network_id":"nabc123","team_id":"te1001","user_id":"uabc123","email":"","password":"","firstname":"","lastname":"","notification_email":"","script_id":"","dt_last_run":"","os":"Ubuntu 18.04.3 LTS","ram":"4294967296","cpus":1,"gpu":"","storage_total":"53687091200","storage_used":"0","usage_rate":"C2 Hourly","shutdown_timeout_in_hours":1,"shutdown_timeout_forces":false,"perform_auto_snapshot":true,"auto_snapshot_frequency":"week","auto_snapshot_save_count":"2","agent_type":"LinuxHost","dt_created":"2021-03-08T17:59:03.276Z","state":"ready","private_ip_address":"10.64.0.2","public_ip_address":"","live_forever":false,"is_managed":false,"timeouts":{"create":null,"delete":null}}`,
+			expected: map[string]tftypes.Value{
+				"id":                        tftypes.NewValue(tftypes.String, "psabc123"),
+				"size":                      tftypes.NewValue(tftypes.Number, big.NewFloat(50)),
+				"cpus":                      tftypes.NewValue(tftypes.Number, big.NewFloat(1)),
+				"shutdown_timeout_in_hours": tftypes.NewValue(tftypes.Number, big.NewFloat(1)),
+				"auto_snapshot_save_count":  tftypes.NewValue(tftypes.Number, big.NewFloat(2)),
+				"assign_public_ip":          tftypes.NewValue(tftypes.Bool, true),
+				"email":                     tftypes.NewValue(tftypes.String, nil),
+				"script_id":                 tftypes.NewValue(tftypes.String, nil),
+				"live_forever":              tftypes.NewValue(tftypes.Bool, nil),
+				"power_state":               tftypes.NewValue(tftypes.String, nil),
+			},
+		},
+		{
+			typeName: "paperspace_machine",
+			version:  1,
+			rawState: `{"id":"psabc123","region":"East Coast (NY2)","machine_type":"C2","size":50,"billing_type":"hourly","name":"tf-machine","template_id":"tubuntu1","assign_public_ip":false,"network_id":"nabc123","team_id":"te1001","user_id":"uabc123","email":"","password":"","firstname":"","lastname":"","notification_email":"","script_id":"","dt_last_run":"","os":"Ubuntu 18.04.3 LTS","ram":"4294967296","cpus":1,"gpu":"","storage_total":"53687091200","storage_used":"0","usage_rate":"C2 Hourly","shutdown_timeout_in_hours":1,"shutdown_timeout_forces":false,"perform_auto_snapshot":true,"auto_snapshot_frequency":"week","auto_snapshot_save_count":2,"agent_type":"LinuxHost","dt_created":"2021-03-08T17:59:03.276Z","state":"ready","power_state":"running","private_ip_address":"10.64.0.2","public_ip_address":"","live_forever":true,"is_managed":false,"timeouts":{"create":null,"delete":null}}`,
+			expected: map[string]tftypes.Value{
+				"auto_snapshot_save_count": tftypes.NewValue(tftypes.Number, big.NewFloat(2)),
+				"assign_public_ip":         tftypes.NewValue(tftypes.Bool, nil),
+				"password":                 tftypes.NewValue(tftypes.String, nil),
+				"script_id":                tftypes.NewValue(tftypes.String, nil),
+				"live_forever":             tftypes.NewValue(tftypes.Bool, true),
+				"power_state":              tftypes.NewValue(tftypes.String, "running"),
+			},
+		},
+		{
+			typeName: "paperspace_script",
+			rawState: `{"id":"scabc123","name":"tf-script","description":"","script_text":"#!/bin/bash\necho hello","owner_type":"team","owner_id":"te1001","dt_created":"2021-03-08T17:59:03.276Z","is_enabled":true,"run_once":false}`,
+			expected: map[string]tftypes.Value{
+				"id":            tftypes.NewValue(tftypes.String, "scabc123"),
+				"description":   tftypes.NewValue(tftypes.String, nil),
+				"script_text":   tftypes.NewValue(tftypes.String, "#!/bin/bash\necho hello"),
+				"script_file":   tftypes.NewValue(tftypes.String, nil),
+				"script_sha256": tftypes.NewValue(tftypes.String, nil),
+				"is_enabled":    tftypes.NewValue(tftypes.Bool, true),
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(fmt.Sprintf("%s_v%d", tc.typeName, tc.version), func(t *testing.T) {
+			attributes := testUpgradeResourceState(t, tc.typeName, tc.version, tc.rawState)
+			for name, expected := range tc.expected {
+				if !attributes[name].Equal(expected) {
+					t.Errorf("%s: expected %s, got %s", name, expected, attributes[name])
+				}
+			}
+		})
+	}
+}
+
+// TestProvider_upgradeMachineState checks that machine state written by the
+// SDK based releases, which stored auto_snapshot_save_count as a string,
+// is migrated.
+func TestProvider_upgradeMachineState(t *testing.T) {
+	for saveCount, expected := range map[string]tftypes.Value{
+		`""`:  tftypes.NewValue(tftypes.Number, nil),
+		`"5"`: tftypes.NewValue(tftypes.Number, big.NewFloat(5)),
+	} {
+		attributes := testUpgradeResourceState(t, "paperspace_machine", 0,
+			`{"id":"psabc123","machine_type":"C2","size":50,"auto_snapshot_save_count":`+saveCount+`}`)
+		if !attributes["auto_snapshot_save_count"].Equal(expected) {
+			t.Errorf("auto_snapshot_save_count %s: expected %s, got %s", saveCount, expected, attributes["auto_snapshot_save_count"])
+		}
+	}
+}
+
+// testUpgradeResourceState upgrades raw state of the given schema version
+// and returns the attributes of the result.
+func testUpgradeResourceState(t *testing.T, typeName string, version int64, rawState string) map[string]tftypes.Value {
+	t.Helper()
+
+	server, err := providerserver.NewProtocol6WithError(New("test")())()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	resp, err := server.UpgradeResourceState(context.Background(), &tfprotov6.UpgradeResourceStateRequest{
+		TypeName: typeName,
+		Version:  version,
+		RawState: &tfprotov6.RawState{JSON: []byte(rawState)},
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	for _, d := range resp.Diagnostics {
+		t.Fatalf("%s: %s: %s", d.Severity, d.Summary, d.Detail)
+	}
+
+	schemaResp, err := server.GetProviderSchema(context.Background(), &tfprotov6.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	state, err := resp.UpgradedState.Unmarshal(schemaResp.ResourceSchemas[typeName].ValueType())
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	var attributes map[string]tftypes.Value
+	if err := state.As(&attributes); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	return attributes
+}
+
+// testAccTempDir points the test's Terraform working directories at a
+// directory of its own, so their state can be found by testAccRewriteState.
+func testAccTempDir(t *testing.T) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	t.Setenv("TF_ACC_TEMP_DIR", dir)
+
+	return dir
+}
+
+// testAccRewriteState returns a PreConfig that rewrites the saved state of
+// every typeName instance under tempDir as an older release would have
+// written it: with the given schema version and attribute values.
+func testAccRewriteState(t *testing.T, tempDir, typeName string, version int, attributes map[string]interface{}) func() {
+	return func() {
+		paths, err := filepath.Glob(filepath.Join(tempDir, "plugintest*", "work*", "terraform.tfstate"))
+		if err != nil || len(paths) == 0 {
+			t.Fatalf("no saved state under %s: %v", tempDir, err)
+		}
+
+		for _, p := range paths {
+			data, err := os.ReadFile(p)
+			if err != nil {
+				t.Fatalf("err: %s", err)
+			}
+
+			var state map[string]interface{}
+			if err := json.Unmarshal(data, &state); err != nil {
+				t.Fatalf("err: %s", err)
+			}
+
+			resources, _ := state["resources"].([]interface{})
+			for _, r := range resources {
+				r := r.(map[string]interface{})
+				if r["type"] != typeName {
+					continue
+				}
+
+				for _, instance := range r["instances"].([]interface{}) {
+					instance := instance.(map[string]interface{})
+					instance["schema_version"] = version
+					for name, value := range attributes {
+						instance["attributes"].(map[string]interface{})[name] = value
+					}
+				}
+			}
+
+			data, err = json.Marshal(state)
+			if err != nil {
+				t.Fatalf("err: %s", err)
+			}
+			if err := os.WriteFile(p, data, 0o644); err != nil {
+				t.Fatalf("err: %s", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package provider
+
+import (
+	"context"
+	"log"
+	"math"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const defaultRequestsPerSecond = 5.0
+
+// rateLimiter is a token bucket shared by every client created from the same
+// provider configuration, so that parallel resources and polling loops stay
+// under the API rate limit together.
+type rateLimiter struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newRateLimiter(requestsPerSecond float64) *rateLimiter {
+	burst := math.Max(1, math.Ceil(requestsPerSecond))
+
+	return &rateLimiter{
+		rate:   requestsPerSecond,
+		burst:  burst,
+		tokens: burst,
+		last:   time.Now(),
+	}
+}
+
+// reserve takes a token and returns how long the caller has to wait before
+// it may use it.
+func (l *rateLimiter) reserve() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	l.tokens = math.Min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	l.last = now
+	l.tokens--
+
+	if l.tokens >= 0 {
+		return 0
+	}
+
+	return time.Duration(-l.tokens / l.rate * float64(time.Second))
+}
+
+// release hands back a token reserved by a caller that gave up waiting, so
+// that a cancelled request does not slow down the ones queued behind it.
+func (l *rateLimiter) release() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.tokens = math.Min(l.burst, l.tokens+1)
+}
+
+func (l *rateLimiter) Wait(ctx context.Context, desc string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	wait := l.reserve()
+	if wait <= 0 {
+		return nil
+	}
+
+	log.Printf("[DEBUG] paperspace client throttling %s for %s to stay under %.2f requests per second", desc, wait, l.rate)
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		l.release()
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+type rateLimitTransport struct {
+	next    http.RoundTripper
+	limiter *rateLimiter
+}
+
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.limiter.Wait(req.Context(), req.Method+" "+req.URL.Path); err != nil {
+		return nil, err
+	}
+
+	return t.next.RoundTrip(req)
+}
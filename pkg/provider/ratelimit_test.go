@@ -0,0 +1,83 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRateLimiter_throughput(t *testing.T) {
+	const rate = 20.0
+	limiter := newRateLimiter(rate)
+
+	// the first burst goes through at once, the rest at rate
+	const requests = 40
+	start := time.Now()
+	for i := 0; i < requests; i++ {
+		if err := limiter.Wait(context.Background(), "test"); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+	}
+	elapsed := time.Since(start)
+
+	minElapsed := time.Duration(float64(requests-int(limiter.burst)) / rate * float64(time.Second))
+	if elapsed < minElapsed*9/10 {
+		t.Errorf("expected %d requests to take at least %s at %.0f requests per second, took %s", requests, minElapsed, rate, elapsed)
+	}
+	if elapsed > minElapsed*3 {
+		t.Errorf("expected %d requests to take about %s, took %s", requests, minElapsed, elapsed)
+	}
+}
+
+func TestRateLimiter_cancel(t *testing.T) {
+	limiter := newRateLimiter(0.1)
+	if err := limiter.Wait(context.Background(), "test"); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := limiter.Wait(ctx, "test")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the wait to end with the context, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected the wait to stop promptly, took %s", elapsed)
+	}
+}
+
+func TestRateLimiter_cancelReleasesToken(t *testing.T) {
+	limiter := newRateLimiter(0.1)
+	if err := limiter.Wait(context.Background(), "test"); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := limiter.Wait(ctx, "test"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the wait to end with the context, got %v", err)
+	}
+
+	// only the first request used a token, so the next one waits for a
+	// single refill rather than two
+	if wait := limiter.reserve(); wait > 11*time.Second {
+		t.Errorf("expected the cancelled wait to give its token back, next wait is %s", wait)
+	}
+}
+
+func TestRateLimiter_cancelledBeforeWait(t *testing.T) {
+	limiter := newRateLimiter(1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := limiter.Wait(ctx, "test"); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected a cancelled context to fail the wait, got %v", err)
+	}
+
+	if wait := limiter.reserve(); wait != 0 {
+		t.Errorf("expected the token to still be available, next wait is %s", wait)
+	}
+}
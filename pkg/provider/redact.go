@@ -0,0 +1,179 @@
+package provider
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httputil"
+	"strings"
+)
+
+const redactedValue = "[REDACTED]"
+
+// sensitiveKeyParts matches header names and JSON keys whose values must
+// never reach the logs. Keys are compared lowercased with "-" and "_"
+// removed, so "x-api-key", "apiKey" and "api_key" all match "apikey".
+var sensitiveKeyParts = []string{
+	"apikey",
+	"password",
+	"secret",
+	"token",
+	"authorization",
+	"cookie",
+}
+
+func isSensitiveKey(key string) bool {
+	normalized := strings.NewReplacer("-", "", "_", "").Replace(strings.ToLower(key))
+	for _, part := range sensitiveKeyParts {
+		if strings.Contains(normalized, part) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// redactHeaders returns a copy of h with sensitive header values masked.
+func redactHeaders(h http.Header) http.Header {
+	redacted := h.Clone()
+	for k := range redacted {
+		if isSensitiveKey(k) {
+			redacted[k] = []string{redactedValue}
+		}
+	}
+
+	return redacted
+}
+
+// redactValue returns a copy of v with sensitive map keys masked at any
+// depth. Structs and other values are round-tripped through JSON first so
+// that their JSON field names are checked.
+func redactValue(v interface{}) interface{} {
+	switch value := v.(type) {
+	case nil, string, bool, float64, json.Number:
+		return value
+	case map[string]interface{}:
+		redacted := make(map[string]interface{}, len(value))
+		for k, item := range value {
+			if isSensitiveKey(k) && item != nil {
+				redacted[k] = redactedValue
+				continue
+			}
+			redacted[k] = redactValue(item)
+		}
+		return redacted
+	case []interface{}:
+		redacted := make([]interface{}, len(value))
+		for i, item := range value {
+			redacted[i] = redactValue(item)
+		}
+		return redacted
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("<unloggable %T>", v)
+	}
+
+	var generic interface{}
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return fmt.Sprintf("<unloggable %T>", v)
+	}
+
+	return redactValue(generic)
+}
+
+// redactJSON masks sensitive fields in a JSON document. Anything that is not
+// valid JSON is returned unchanged.
+func redactJSON(data []byte) string {
+	if len(bytes.TrimSpace(data)) == 0 {
+		return string(data)
+	}
+
+	var generic interface{}
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return string(data)
+	}
+
+	redacted, err := json.Marshal(redactValue(generic))
+	if err != nil {
+		return string(data)
+	}
+
+	return string(redacted)
+}
+
+// dumpRequest is httputil.DumpRequest with sensitive headers and body fields
+// masked.
+func dumpRequest(req *http.Request) (string, error) {
+	var body []byte
+	if req.GetBody != nil {
+		reader, err := req.GetBody()
+		if err != nil {
+			return "", err
+		}
+		defer reader.Close()
+
+		body, err = io.ReadAll(reader)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	clone := req.Clone(req.Context())
+	clone.Header = redactHeaders(req.Header)
+	clone.Body = nil
+
+	dump, err := httputil.DumpRequest(clone, false)
+	if err != nil {
+		return "", err
+	}
+
+	return string(dump) + redactJSON(body), nil
+}
+
+// dumpResponse is httputil.DumpResponse with sensitive headers and body
+// fields masked. The response body remains readable afterwards.
+func dumpResponse(resp *http.Response) string {
+	var body []byte
+	if resp.Body != nil {
+		body, _ = io.ReadAll(resp.Body)
+		resp.Body.Close()
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	clone := *resp
+	clone.Header = redactHeaders(resp.Header)
+	clone.Body = nil
+
+	dump, err := httputil.DumpResponse(&clone, false)
+	if err != nil {
+		return fmt.Sprintf("<unloggable response: %s>", err)
+	}
+
+	return string(dump) + redactJSON(body)
+}
+
+// loggingTransport logs requests and responses with secrets redacted.
+type loggingTransport struct {
+	next http.RoundTripper
+}
+
+func (t *loggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if dump, err := dumpRequest(req); err == nil {
+		log.Printf("[DEBUG] paperspace request: %s", dump)
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		log.Printf("[DEBUG] paperspace request %s %s failed: %s", req.Method, req.URL, err)
+		return resp, err
+	}
+
+	log.Printf("[DEBUG] paperspace response: %s", dumpResponse(resp))
+
+	return resp, nil
+}
@@ -0,0 +1,129 @@
+package provider
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRedactHeaders(t *testing.T) {
+	cases := []struct {
+		name     string
+		header   string
+		value    string
+		redacted bool
+	}{
+		{name: "api key", header: "X-Api-Key", value: "secret-key", redacted: true},
+		{name: "lowercase api key", header: "x-api-key", value: "secret-key", redacted: true},
+		{name: "authorization", header: "Authorization", value: "Bearer abc", redacted: true},
+		{name: "cookie", header: "Cookie", value: "session=abc", redacted: true},
+		{name: "content type", header: "Content-Type", value: "application/json", redacted: false},
+		{name: "user agent", header: "User-Agent", value: "terraform-provider-paperspace", redacted: false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			h := http.Header{}
+			h.Set(tc.header, tc.value)
+
+			redacted := redactHeaders(h)
+
+			expected := tc.value
+			if tc.redacted {
+				expected = redactedValue
+			}
+			if got := redacted.Get(tc.header); got != expected {
+				t.Errorf("expected %q, got %q", expected, got)
+			}
+			if got := h.Get(tc.header); got != tc.value {
+				t.Errorf("expected the original header to be left alone, got %q", got)
+			}
+		})
+	}
+}
+
+func TestRedactJSON(t *testing.T) {
+	cases := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "top level password",
+			input:    `{"email":"jane@example.com","password":"hunter2"}`,
+			expected: `{"email":"jane@example.com","password":"[REDACTED]"}`,
+		},
+		{
+			name:     "nested api key",
+			input:    `{"user":{"apiKey":"abc","name":"jane"}}`,
+			expected: `{"user":{"apiKey":"[REDACTED]","name":"jane"}}`,
+		},
+		{
+			name:     "tokens inside arrays",
+			input:    `{"sessions":[{"access_token":"t1","id":1},{"refreshToken":"t2","id":2}]}`,
+			expected: `{"sessions":[{"access_token":"[REDACTED]","id":1},{"id":2,"refreshToken":"[REDACTED]"}]}`,
+		},
+		{
+			name:     "null secrets are kept",
+			input:    `{"password":null}`,
+			expected: `{"password":null}`,
+		},
+		{
+			name:     "nothing sensitive",
+			input:    `[{"id":"ps1","name":"machine"}]`,
+			expected: `[{"id":"ps1","name":"machine"}]`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var got, expected interface{}
+			if err := json.Unmarshal([]byte(redactJSON([]byte(tc.input))), &got); err != nil {
+				t.Fatalf("err: %s", err)
+			}
+			if err := json.Unmarshal([]byte(tc.expected), &expected); err != nil {
+				t.Fatalf("err: %s", err)
+			}
+			if !reflect.DeepEqual(got, expected) {
+				t.Errorf("expected %s, got %s", tc.expected, redactJSON([]byte(tc.input)))
+			}
+		})
+	}
+}
+
+func TestRedactJSON_notJSON(t *testing.T) {
+	for _, input := range []string{
+		"",
+		"  \n",
+		"Bad Gateway",
+		"<html><body>password=hunter2</body></html>",
+		`{"password":`,
+	} {
+		if got := redactJSON([]byte(input)); got != input {
+			t.Errorf("expected %q to pass through unchanged, got %q", input, got)
+		}
+	}
+}
+
+func TestDumpRequest(t *testing.T) {
+	req, err := http.NewRequest("POST", "https://api.paperspace.io/machines/createSingleMachinePublic", strings.NewReader(`{"machineName":"m","password":"hunter2"}`))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	req.Header.Set("X-Api-Key", "secret-key")
+
+	dump, err := dumpRequest(req)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	for _, secret := range []string{"secret-key", "hunter2"} {
+		if strings.Contains(dump, secret) {
+			t.Errorf("expected %q to be redacted from %s", secret, dump)
+		}
+	}
+	if !strings.Contains(dump, `"machineName":"m"`) {
+		t.Errorf("expected the rest of the body to be kept in %s", dump)
+	}
+}
@@ -0,0 +1,119 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Region is a Paperspace region. The API names regions by Name, except for
+// private networks, which take the numeric ID.
+type Region struct {
+	Code string
+	Name string
+	ID   int
+}
+
+// Regions lists every region, in the order of their IDs.
+var Regions = []Region{
+	{Code: "ny2", Name: "East Coast (NY2)", ID: 1},
+	{Code: "ca1", Name: "West Coast (CA1)", ID: 2},
+	{Code: "ams1", Name: "Europe (AMS1)", ID: 3},
+}
+
+// findRegion looks a region up by code or name, ignoring case.
+func findRegion(s string) (Region, bool) {
+	s = strings.TrimSpace(s)
+	for _, region := range Regions {
+		if strings.EqualFold(s, region.Code) || strings.EqualFold(s, region.Name) {
+			return region, true
+		}
+	}
+
+	return Region{}, false
+}
+
+// regionName returns the name the API uses for s, or s itself when it is not
+// a known region.
+func regionName(s string) string {
+	if region, ok := findRegion(s); ok {
+		return region.Name
+	}
+
+	return s
+}
+
+// sameRegion reports whether a and b spell the same region.
+func sameRegion(a, b string) bool {
+	return a == b || regionName(a) == regionName(b)
+}
+
+// regionStateValue returns the region the API reported, keeping prior when it
+// is another spelling of the same region so configurations using codes do not
+// show a diff.
+func regionStateValue(v string, prior types.String) types.String {
+	if !prior.IsNull() && !prior.IsUnknown() && sameRegion(v, prior.ValueString()) {
+		return prior
+	}
+
+	return types.StringValue(v)
+}
+
+// regionChoices describes the accepted spellings for error messages.
+func regionChoices() string {
+	choices := make([]string, 0, len(Regions))
+	for _, region := range Regions {
+		choices = append(choices, fmt.Sprintf("%s (%q)", region.Code, region.Name))
+	}
+
+	return strings.Join(choices, ", ")
+}
+
+// regionValidator checks that a string attribute names a known region, by
+// code or by name.
+type regionValidator struct{}
+
+func (v regionValidator) Description(ctx context.Context) string {
+	return "value must be a region code or name: " + regionChoices()
+}
+
+func (v regionValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v regionValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if _, ok := findRegion(req.ConfigValue.ValueString()); !ok {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid paperspace region",
+			fmt.Sprintf("Region %q not found. Use one of: %s.", req.ConfigValue.ValueString(), regionChoices()))
+	}
+}
+
+// regionPlanModifier keeps the region in state when the configuration spells
+// the same region differently, e.g. "ny2" for "East Coast (NY2)".
+type regionPlanModifier struct{}
+
+func (m regionPlanModifier) Description(ctx context.Context) string {
+	return "Region codes and names for the same region do not cause a diff."
+}
+
+func (m regionPlanModifier) MarkdownDescription(ctx context.Context) string {
+	return m.Description(ctx)
+}
+
+func (m regionPlanModifier) PlanModifyString(ctx context.Context, req planmodifier.StringRequest, resp *planmodifier.StringResponse) {
+	if req.StateValue.IsNull() || req.PlanValue.IsNull() || req.PlanValue.IsUnknown() {
+		return
+	}
+
+	if sameRegion(req.PlanValue.ValueString(), req.StateValue.ValueString()) {
+		resp.PlanValue = req.StateValue
+	}
+}
@@ -1,178 +1,292 @@
 package provider
 
 import (
+	"context"
+	"fmt"
 	"time"
 
 	"github.com/Paperspace/paperspace-go"
-	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
-	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
-func ErrNotFound(err error) bool {
-	paperspaceError, ok := err.(*paperspace.PaperspaceError)
-	if ok {
-		if paperspaceError.Status == 404 {
-			return true
-		}
-	}
+const autoscalingGroupTimeout = 1 * time.Minute
+
+type autoscalingGroupResource struct {
+	meta interface{}
+}
 
-	return false
+type autoscalingGroupResourceModel struct {
+	ID              types.String   `tfsdk:"id"`
+	Name            types.String   `tfsdk:"name"`
+	Min             types.Int64    `tfsdk:"min"`
+	Max             types.Int64    `tfsdk:"max"`
+	ClusterID       types.String   `tfsdk:"cluster_id"`
+	MachineType     types.String   `tfsdk:"machine_type"`
+	TemplateID      types.String   `tfsdk:"template_id"`
+	NetworkID       types.String   `tfsdk:"network_id"`
+	StartupScriptID types.String   `tfsdk:"startup_script_id"`
+	Timeouts        timeouts.Value `tfsdk:"timeouts"`
 }
 
-func resourceAutoscalingGroupCreate(d *schema.ResourceData, m interface{}) error {
+func resourceAutoscalingGroup() resource.Resource {
+	return &autoscalingGroupResource{}
+}
+
+func (r *autoscalingGroupResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_autoscaling_group"
+}
+
+func (r *autoscalingGroupResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	r.meta = req.ProviderData
+}
+
+func (r *autoscalingGroupResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan autoscalingGroupResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	timeout, diags := plan.Timeouts.Create(ctx, autoscalingGroupTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	var autoscalingGroup paperspace.AutoscalingGroup
 
-	paperspaceClient := newPaperspaceClient(m)
+	paperspaceClient := newPaperspaceClient(r.meta)
 	autoscalingGroupCreateParams := paperspace.AutoscalingGroupCreateParams{
-		Name:        d.Get("name").(string),
-		ClusterID:   d.Get("cluster_id").(string),
-		Min:         d.Get("min").(int),
-		Max:         d.Get("max").(int),
-		MachineType: d.Get("machine_type").(string),
-		TemplateID:  d.Get("template_id").(string),
-		NetworkID:   d.Get("network_id").(string),
-		ScriptID:    d.Get("startup_script_id").(string),
+		Name:        plan.Name.ValueString(),
+		ClusterID:   plan.ClusterID.ValueString(),
+		Min:         int(plan.Min.ValueInt64()),
+		Max:         int(plan.Max.ValueInt64()),
+		MachineType: plan.MachineType.ValueString(),
+		TemplateID:  plan.TemplateID.ValueString(),
+		NetworkID:   plan.NetworkID.ValueString(),
+		ScriptID:    plan.StartupScriptID.ValueString(),
 	}
 
-	err := resource.Retry(d.Timeout(schema.TimeoutCreate), func() *resource.RetryError {
+	err := retryContext(ctx, timeout, func(ctx context.Context) *retryError {
 		var err error
+		autoscalingGroupCreateParams.Context = ctx
 		autoscalingGroup, err = paperspaceClient.CreateAutoscalingGroup(autoscalingGroupCreateParams)
 		if err != nil {
-			return resource.RetryableError(err)
+			return retryableError(err)
 		}
 
-		return resource.NonRetryableError(nil)
+		return nonRetryableError(nil)
 	})
 	if err != nil {
-		return err
+		resp.Diagnostics.Append(errorDiagnostic("Error creating paperspace autoscaling group", err))
+		return
 	}
 
-	d.SetId(autoscalingGroup.ID)
+	plan.ID = types.StringValue(autoscalingGroup.ID)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), plan.ID)...)
 
-	return resource.Retry(d.Timeout(schema.TimeoutCreate), func() *resource.RetryError {
-		if err := resourceAutoscalingGroupRead(d, m); err != nil {
-			return resource.RetryableError(err)
-		}
+	resp.Diagnostics.Append(waitForAutoscalingGroup(ctx, paperspaceClient, &plan, timeout)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-		return resource.NonRetryableError(nil)
-	})
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
 }
 
-func resourceAutoscalingGroupRead(d *schema.ResourceData, m interface{}) error {
-	paperspaceClient := newPaperspaceClient(m)
+// readAutoscalingGroup refreshes data from the API, returning false if the
+// autoscaling group no longer exists.
+func readAutoscalingGroup(ctx context.Context, paperspaceClient *paperspace.Client, data *autoscalingGroupResourceModel) (bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
 
-	autoscalingGroup, err := paperspaceClient.GetAutoscalingGroup(d.Id(), paperspace.AutoscalingGroupGetParams{})
+	autoscalingGroup, err := paperspaceClient.GetAutoscalingGroup(data.ID.ValueString(), paperspace.AutoscalingGroupGetParams{
+		RequestParams: paperspace.RequestParams{Context: ctx},
+	})
 	if err != nil {
-		if ErrNotFound(err) {
-			d.SetId("")
-			return nil
+		if IsNotFound(err) {
+			return false, diags
+		}
+
+		diags.Append(errorDiagnostic(fmt.Sprintf("Error reading paperspace autoscaling group %s", data.ID.ValueString()), err))
+		return false, diags
+	}
+
+	data.Name = optionalStringValue(autoscalingGroup.Name, data.Name)
+	data.MachineType = types.StringValue(autoscalingGroup.MachineType)
+	data.TemplateID = types.StringValue(autoscalingGroup.TemplateID)
+	data.NetworkID = types.StringValue(autoscalingGroup.NetworkID)
+	data.StartupScriptID = optionalStringValue(autoscalingGroup.ScriptID, data.StartupScriptID)
+
+	return true, diags
+}
+
+// waitForAutoscalingGroup reads the autoscaling group back into data, retrying
+// until the API serves it or timeout passes.
+func waitForAutoscalingGroup(ctx context.Context, paperspaceClient *paperspace.Client, data *autoscalingGroupResourceModel, timeout time.Duration) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	err := retryContext(ctx, timeout, func(ctx context.Context) *retryError {
+		var found bool
+		found, diags = readAutoscalingGroup(ctx, paperspaceClient, data)
+		if diags.HasError() || !found {
+			return retryableError(nil)
 		}
 
-		return err
+		return nil
+	})
+	if err != nil && !diags.HasError() {
+		diags.Append(errorDiagnostic(fmt.Sprintf("Error reading paperspace autoscaling group %s", data.ID.ValueString()), err))
 	}
 
-	d.Set("name", autoscalingGroup.Name)
-	d.Set("machine_type", autoscalingGroup.MachineType)
-	d.Set("template_id", autoscalingGroup.TemplateID)
-	d.Set("network_id", autoscalingGroup.NetworkID)
-	d.Set("startup_script_id", autoscalingGroup.ScriptID)
+	return diags
+}
+
+func (r *autoscalingGroupResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data autoscalingGroupResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	found, diags := readAutoscalingGroup(ctx, newPaperspaceClient(r.meta), &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if !found {
+		resp.State.RemoveResource(ctx)
+		return
+	}
 
-	return nil
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
-func resourceAutoscalingGroupUpdate(d *schema.ResourceData, m interface{}) error {
-	paperspaceClient := newPaperspaceClient(m)
+func (r *autoscalingGroupResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan autoscalingGroupResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	timeout, diags := plan.Timeouts.Create(ctx, autoscalingGroupTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	paperspaceClient := newPaperspaceClient(r.meta)
 	autoscalingGroupUpdateParams := paperspace.AutoscalingGroupUpdateParams{
 		Attributes: paperspace.AutoscalingGroupUpdateAttributeParams{
-			Name:       d.Get("name").(string),
-			TemplateID: d.Get("template_id").(string),
-			NetworkID:  d.Get("network_id").(string),
-			ScriptID:   d.Get("startup_script_id").(string),
+			Name:       plan.Name.ValueString(),
+			TemplateID: plan.TemplateID.ValueString(),
+			NetworkID:  plan.NetworkID.ValueString(),
+			ScriptID:   plan.StartupScriptID.ValueString(),
 		},
 	}
 
-	err := resource.Retry(d.Timeout(schema.TimeoutCreate), func() *resource.RetryError {
-		if err := paperspaceClient.UpdateAutoscalingGroup(d.Id(), autoscalingGroupUpdateParams); err != nil {
-			return resource.RetryableError(err)
+	err := retryContext(ctx, timeout, func(ctx context.Context) *retryError {
+		autoscalingGroupUpdateParams.Context = ctx
+		if err := paperspaceClient.UpdateAutoscalingGroup(plan.ID.ValueString(), autoscalingGroupUpdateParams); err != nil {
+			return retryableError(err)
 		}
 
-		return resource.NonRetryableError(nil)
+		return nonRetryableError(nil)
 	})
 	if err != nil {
-		return err
+		resp.Diagnostics.Append(errorDiagnostic(fmt.Sprintf("Error updating paperspace autoscaling group %s", plan.ID.ValueString()), err))
+		return
 	}
 
-	return resource.Retry(d.Timeout(schema.TimeoutCreate), func() *resource.RetryError {
-		if err := resourceAutoscalingGroupRead(d, m); err != nil {
-			return resource.RetryableError(err)
-		}
-
-		return resource.NonRetryableError(nil)
-	})
+	resp.Diagnostics.Append(waitForAutoscalingGroup(ctx, paperspaceClient, &plan, timeout)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
 }
 
-func resourceAutoscalingGroupDelete(d *schema.ResourceData, m interface{}) error {
-	paperspaceClient := newPaperspaceClient(m)
+func (r *autoscalingGroupResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data autoscalingGroupResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-	return resource.Retry(d.Timeout(schema.TimeoutDelete), func() *resource.RetryError {
-		if err := paperspaceClient.DeleteAutoscalingGroup(d.Id(), paperspace.AutoscalingGroupDeleteParams{}); err != nil {
-			if ErrNotFound(err) {
-				return resource.NonRetryableError(nil)
+	timeout, diags := data.Timeouts.Delete(ctx, autoscalingGroupTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	paperspaceClient := newPaperspaceClient(r.meta)
+
+	err := retryContext(ctx, timeout, func(ctx context.Context) *retryError {
+		if err := paperspaceClient.DeleteAutoscalingGroup(data.ID.ValueString(), paperspace.AutoscalingGroupDeleteParams{
+			RequestParams: paperspace.RequestParams{Context: ctx},
+		}); err != nil {
+			if IsNotFound(err) {
+				return nonRetryableError(nil)
+			}
+			if !IsRetryable(err) {
+				return nonRetryableError(err)
 			}
-			return resource.RetryableError(err)
+			return retryableError(err)
 		}
 
-		return resource.NonRetryableError(nil)
+		return nonRetryableError(nil)
 	})
+	if err != nil {
+		resp.Diagnostics.Append(errorDiagnostic(fmt.Sprintf("Error deleting paperspace autoscaling group %s", data.ID.ValueString()), err))
+	}
 }
 
-func resourceAutoscalingGroup() *schema.Resource {
-	return &schema.Resource{
-		Create: resourceAutoscalingGroupCreate,
-		Read:   resourceAutoscalingGroupRead,
-		Update: resourceAutoscalingGroupUpdate,
-		Delete: resourceAutoscalingGroupDelete,
-		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
-		},
-		Schema: map[string]*schema.Schema{
-			"name": &schema.Schema{
-				Type:     schema.TypeString,
+func (r *autoscalingGroupResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+func (r *autoscalingGroupResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:      true,
+				PlanModifiers: []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
+			},
+			"name": schema.StringAttribute{
 				Optional: true,
 			},
-			"min": &schema.Schema{
-				Type:     schema.TypeInt,
+			"min": schema.Int64Attribute{
 				Required: true,
 			},
-			"max": &schema.Schema{
-				Type:     schema.TypeInt,
+			"max": schema.Int64Attribute{
 				Required: true,
 			},
-			"cluster_id": &schema.Schema{
-				Type:     schema.TypeString,
+			"cluster_id": schema.StringAttribute{
 				Required: true,
 			},
-			"machine_type": &schema.Schema{
-				Type:     schema.TypeString,
+			"machine_type": schema.StringAttribute{
 				Required: true,
 			},
-			"template_id": &schema.Schema{
-				Type:     schema.TypeString,
+			"template_id": schema.StringAttribute{
 				Required: true,
 			},
-			"network_id": &schema.Schema{
-				Type:     schema.TypeString,
+			"network_id": schema.StringAttribute{
 				Required: true,
 			},
-			"startup_script_id": &schema.Schema{
-				Type:     schema.TypeString,
+			"startup_script_id": schema.StringAttribute{
 				Optional: true,
 			},
 		},
-		Timeouts: &schema.ResourceTimeout{
-			Create: schema.DefaultTimeout(1 * time.Minute),
-			Delete: schema.DefaultTimeout(1 * time.Minute),
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Delete: true,
+			}),
 		},
 	}
 }
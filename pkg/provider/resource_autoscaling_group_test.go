@@ -0,0 +1,87 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestAccAutoscalingGroup_basic(t *testing.T) {
+	api := newFakeAPI(t)
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories(),
+		CheckDestroy:             testAccCheckAutoscalingGroupDestroy(api),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccAutoscalingGroupConfig(api, "tf-acc-asg"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckAutoscalingGroupExists(api, "paperspace_autoscaling_group.test"),
+					resource.TestCheckResourceAttr("paperspace_autoscaling_group.test", "name", "tf-acc-asg"),
+					resource.TestCheckResourceAttr("paperspace_autoscaling_group.test", "machine_type", "P4000"),
+					resource.TestCheckResourceAttr("paperspace_autoscaling_group.test", "startup_script_id", "sc123"),
+				),
+			},
+			{
+				Config: testAccAutoscalingGroupConfig(api, "tf-acc-asg-renamed"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("paperspace_autoscaling_group.test", "name", "tf-acc-asg-renamed"),
+				),
+			},
+			{
+				Config:            testAccAutoscalingGroupConfig(api, "tf-acc-asg-renamed"),
+				ResourceName:      "paperspace_autoscaling_group.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+				// not read back by resourceAutoscalingGroupRead
+				ImportStateVerifyIgnore: []string{"cluster_id", "min", "max"},
+			},
+		},
+	})
+}
+
+func testAccAutoscalingGroupConfig(api *fakeAPI, name string) string {
+	return api.providerConfig() + fmt.Sprintf(`
+resource "paperspace_autoscaling_group" "test" {
+  name              = %q
+  cluster_id        = "cl123"
+  min               = 1
+  max               = 3
+  machine_type      = "P4000"
+  template_id       = "tubuntu1"
+  network_id        = "nabc123"
+  startup_script_id = "sc123"
+}
+`, name)
+}
+
+func testAccCheckAutoscalingGroupExists(api *fakeAPI, name string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("Not found: %s", name)
+		}
+		if !api.autoscalingGroupExists(rs.Primary.ID) {
+			return fmt.Errorf("Autoscaling group %s does not exist", rs.Primary.ID)
+		}
+
+		return nil
+	}
+}
+
+func testAccCheckAutoscalingGroupDestroy(api *fakeAPI) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		for _, rs := range s.RootModule().Resources {
+			if rs.Type != "paperspace_autoscaling_group" {
+				continue
+			}
+			if api.autoscalingGroupExists(rs.Primary.ID) {
+				return fmt.Errorf("Autoscaling group %s still exists", rs.Primary.ID)
+			}
+		}
+
+		return nil
+	}
+}
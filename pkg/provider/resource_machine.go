@@ -1,327 +1,784 @@
 package provider
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 	"log"
+	"strconv"
 	"strings"
 	"time"
 
-	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
-	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
-func resourceMachineCreate(d *schema.ResourceData, m interface{}) error {
-	paperspaceClient := newInternalPaperspaceClient(m)
+const (
+	machineCreateTimeout = 10 * time.Minute
+	machineUpdateTimeout = 20 * time.Minute
+	machineDeleteTimeout = 5 * time.Minute
+
+	machineMaxShutdownTimeoutInHours = 168
+)
+
+type machineResource struct {
+	meta interface{}
+}
+
+type machineResourceModel struct {
+	ID                     types.String   `tfsdk:"id"`
+	Region                 types.String   `tfsdk:"region"`
+	MachineType            types.String   `tfsdk:"machine_type"`
+	Size                   types.Int64    `tfsdk:"size"`
+	BillingType            types.String   `tfsdk:"billing_type"`
+	Name                   types.String   `tfsdk:"name"`
+	TemplateID             types.String   `tfsdk:"template_id"`
+	AssignPublicIP         types.Bool     `tfsdk:"assign_public_ip"`
+	NetworkID              types.String   `tfsdk:"network_id"`
+	TeamID                 types.String   `tfsdk:"team_id"`
+	UserID                 types.String   `tfsdk:"user_id"`
+	Email                  types.String   `tfsdk:"email"`
+	Password               types.String   `tfsdk:"password"`
+	FirstName              types.String   `tfsdk:"firstname"`
+	LastName               types.String   `tfsdk:"lastname"`
+	NotificationEmail      types.String   `tfsdk:"notification_email"`
+	ScriptID               types.String   `tfsdk:"script_id"`
+	DtLastRun              types.String   `tfsdk:"dt_last_run"`
+	OS                     types.String   `tfsdk:"os"`
+	RAM                    types.String   `tfsdk:"ram"`
+	CPUs                   types.Int64    `tfsdk:"cpus"`
+	GPU                    types.String   `tfsdk:"gpu"`
+	StorageTotal           types.String   `tfsdk:"storage_total"`
+	StorageUsed            types.String   `tfsdk:"storage_used"`
+	UsageRate              types.String   `tfsdk:"usage_rate"`
+	ShutdownTimeoutInHours types.Int64    `tfsdk:"shutdown_timeout_in_hours"`
+	ShutdownTimeoutForces  types.Bool     `tfsdk:"shutdown_timeout_forces"`
+	PerformAutoSnapshot    types.Bool     `tfsdk:"perform_auto_snapshot"`
+	AutoSnapshotFrequency  types.String   `tfsdk:"auto_snapshot_frequency"`
+	AutoSnapshotSaveCount  types.Int64    `tfsdk:"auto_snapshot_save_count"`
+	AgentType              types.String   `tfsdk:"agent_type"`
+	DtCreated              types.String   `tfsdk:"dt_created"`
+	State                  types.String   `tfsdk:"state"`
+	PowerState             types.String   `tfsdk:"power_state"`
+	PrivateIPAddress       types.String   `tfsdk:"private_ip_address"`
+	PublicIPAddress        types.String   `tfsdk:"public_ip_address"`
+	LiveForever            types.Bool     `tfsdk:"live_forever"`
+	IsManaged              types.Bool     `tfsdk:"is_managed"`
+	Timeouts               timeouts.Value `tfsdk:"timeouts"`
+}
+
+func resourceMachine() resource.Resource {
+	return &machineResource{}
+}
+
+func (r *machineResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_machine"
+}
+
+func (r *machineResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	r.meta = req.ProviderData
+}
+
+func (r *machineResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan machineResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	timeout, diags := plan.Timeouts.Create(ctx, machineCreateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	paperspaceClient := newInternalPaperspaceClient(r.meta)
 
 	region := paperspaceClient.Region
-	if r, ok := d.GetOk("region"); ok {
-		region = r.(string)
+	if v := plan.Region.ValueString(); v != "" {
+		region = v
 	}
 	if region == "" {
-		return fmt.Errorf("Error creating paperspace machine: missing region")
-	}
-
-	body := make(MapIf)
-	body.AppendV(d, "region", region)
-	body.AppendAs(d, "machine_type", "machineType")
-	body.Append(d, "size")
-	body.AppendAs(d, "billing_type", "billingType")
-	body.AppendAs(d, "name", "machineName")
-	body.AppendAs(d, "template_id", "templateId")
-	body.AppendAsIfSet(d, "assign_public_ip", "assignPublicIp")
-	body.AppendAsIfSet(d, "user_id", "userId")
-	body.AppendAsIfSet(d, "team_id", "teamId")
-	body.AppendAsIfSet(d, "script_id", "scriptId")
-	body.AppendAsIfSet(d, "network_id", "networkId")
-	body.AppendAsIfSet(d, "shutdown_timeout_in_hours", "shutdownTimeoutInHours")
-	body.AppendAsIfSet(d, "is_managed", "isManaged")
-	body.AppendAsIfSet(d, "perform_auto_snapshot", "performAutoSnapshot")
-	body.AppendAsIfSet(d, "auto_snapshot_frequency", "autoSnapshotFrequency")
-	body.AppendAsIfSet(d, "auto_snapshot_save_count", "autoSnapshotSaveCount")
-
-	s := d.Get("live_forever")
-	if s.(bool) == true {
-		body["shutdownTimeoutInHours"] = nil
-	}
-
-	// fields not tested when this project was picked back up for https://github.com/Paperspace/terraform-provider-paperspace/pull/3
-	body.AppendIfSet(d, "email")
-	body.AppendIfSet(d, "password")
-	body.AppendAsIfSet(d, "firstname", "firstName")
-	body.AppendAsIfSet(d, "lastname", "lastName")
-	body.AppendAsIfSet(d, "notification_email", "notificationEmail")
-
-	data, _ := json.MarshalIndent(body, "", "  ")
-
-	id, err := paperspaceClient.CreateMachine(data)
+		resp.Diagnostics.AddAttributeError(path.Root("region"), "Missing paperspace machine region",
+			"The machine has no region. Set region on the resource, set region in the provider configuration, or export PAPERSPACE_REGION.")
+		return
+	}
+
+	params := MachineCreateParams{
+		Region:                 regionName(region),
+		MachineType:            plan.MachineType.ValueString(),
+		Size:                   int(plan.Size.ValueInt64()),
+		BillingType:            plan.BillingType.ValueString(),
+		Name:                   plan.Name.ValueString(),
+		TemplateID:             plan.TemplateID.ValueString(),
+		AssignPublicIP:         plan.AssignPublicIP.ValueBool(),
+		UserID:                 plan.UserID.ValueString(),
+		TeamID:                 plan.TeamID.ValueString(),
+		ScriptID:               plan.ScriptID.ValueString(),
+		NetworkID:              plan.NetworkID.ValueString(),
+		ShutdownTimeoutInHours: int(plan.ShutdownTimeoutInHours.ValueInt64()),
+		IsManaged:              plan.IsManaged.ValueBool(),
+		PerformAutoSnapshot:    plan.PerformAutoSnapshot.ValueBool(),
+		AutoSnapshotFrequency:  plan.AutoSnapshotFrequency.ValueString(),
+		AutoSnapshotSaveCount:  int(plan.AutoSnapshotSaveCount.ValueInt64()),
+		LiveForever:            plan.LiveForever.ValueBool(),
+
+		// fields not tested when this project was picked back up for https://github.com/Paperspace/terraform-provider-paperspace/pull/3
+		Email:             plan.Email.ValueString(),
+		Password:          plan.Password.ValueString(),
+		FirstName:         plan.FirstName.ValueString(),
+		LastName:          plan.LastName.ValueString(),
+		NotificationEmail: plan.NotificationEmail.ValueString(),
+	}
+
+	machine, err := paperspaceClient.CreateMachine(ctx, params)
+	if err != nil {
+		resp.Diagnostics.Append(errorDiagnostic("Error creating paperspace machine", err))
+		return
+	}
+	id := machine.ID
+	plan.ID = types.StringValue(id)
+
+	// track the machine even if waiting for it fails, so it is not leaked
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), id)...)
+
+	if err := waitForMachineState(ctx, paperspaceClient, id, "ready", timeout); err != nil {
+		resp.Diagnostics.Append(errorDiagnostic(fmt.Sprintf("Error waiting for paperspace machine %s to be ready", id), err))
+		return
+	}
+
+	if powerState := plan.PowerState.ValueString(); powerState != "" {
+		resp.Diagnostics.Append(setMachinePowerState(ctx, paperspaceClient, id, powerState, timeout)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	_, diags = readMachine(ctx, paperspaceClient, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// machinePowerStates maps the power_state values accepted in config to the
+// machine state reported by the API once the transition has completed.
+var machinePowerStates = map[string]string{
+	"running": "ready",
+	"off":     "off",
+}
+
+// setMachinePowerState starts or stops the machine so that it ends up in the
+// given power state, waiting until the API reports the matching state.
+func setMachinePowerState(ctx context.Context, paperspaceClient PaperspaceClient, id, powerState string, timeout time.Duration) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	target, ok := machinePowerStates[powerState]
+	if !ok {
+		diags.AddAttributeError(path.Root("power_state"), "Invalid paperspace machine power state", fmt.Sprintf("unknown power_state %s", powerState))
+		return diags
+	}
+
+	machine, err := paperspaceClient.GetMachine(ctx, id)
+	if err != nil {
+		diags.Append(errorDiagnostic(fmt.Sprintf("Error reading paperspace machine %s", id), err))
+		return diags
+	}
+	if machine.State == target {
+		return diags
+	}
+
+	log.Printf("[INFO] paperspace setMachinePowerState setting machine %s power state to %s", id, powerState)
+	if powerState == "running" {
+		err = paperspaceClient.StartMachine(ctx, id)
+	} else {
+		err = paperspaceClient.StopMachine(ctx, id)
+	}
 	if err != nil {
-		return err
+		diags.Append(attributeErrorDiagnostic(path.Root("power_state"), fmt.Sprintf("Error setting paperspace machine %s power state to %s", id, powerState), err))
+		return diags
 	}
-	d.SetId(id)
 
-	return resource.Retry(d.Timeout(schema.TimeoutCreate), func() *resource.RetryError {
-		body, err := paperspaceClient.GetMachine(id)
+	if err := waitForMachineState(ctx, paperspaceClient, id, target, timeout); err != nil {
+		diags.Append(attributeErrorDiagnostic(path.Root("power_state"), fmt.Sprintf("Error waiting for paperspace machine %s power state %s", id, powerState), err))
+	}
+
+	return diags
+}
+
+func waitForMachineState(ctx context.Context, paperspaceClient PaperspaceClient, id, target string, timeout time.Duration) error {
+	return retryContext(ctx, timeout, func(ctx context.Context) *retryError {
+		machine, err := paperspaceClient.GetMachine(ctx, id)
 		if err != nil {
-			return resource.RetryableError(err)
+			return retryableError(err)
 		}
 
-		state, ok := body["state"].(string)
-		if !ok {
-			return resource.RetryableError(fmt.Errorf("[WARNING] Expected machine to be ready but found no state"))
+		if machine.State == "" {
+			return retryableError(fmt.Errorf("[WARNING] Expected machine to be %s but found no state", target))
 		}
-		if state != "ready" {
-			return resource.RetryableError(fmt.Errorf("[INFO] Expected machine to be ready but was in state %s", state))
+		if machine.State != target {
+			return retryableError(fmt.Errorf("[INFO] Expected machine to be %s but was in state %s", target, machine.State))
 		}
 
-		return resource.NonRetryableError(resourceMachineRead(d, m))
+		return nil
 	})
 }
 
-func resourceMachineRead(d *schema.ResourceData, m interface{}) error {
-	paperspaceClient := newInternalPaperspaceClient(m)
+// readMachine refreshes data from the API, returning false if the machine no
+// longer exists. Arguments the API does not return are left untouched.
+func readMachine(ctx context.Context, paperspaceClient PaperspaceClient, data *machineResourceModel) (bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
 
-	body, err := paperspaceClient.GetMachine(d.Id())
+	machine, err := paperspaceClient.GetMachine(ctx, data.ID.ValueString())
 	if err != nil {
-		if err.Error() == MachineNotFoundError {
-			d.SetId("")
-			return nil
+		if IsNotFound(err) {
+			return false, diags
+		}
+
+		diags.Append(errorDiagnostic(fmt.Sprintf("Error reading paperspace machine %s", data.ID.ValueString()), err))
+		return false, diags
+	}
+
+	data.Name = types.StringValue(machine.Name)
+	data.MachineType = machineTypeStateValue(machine.UsageRate, data.MachineType)
+	data.Size = machineSizeStateValue(machine.StorageTotal, data.Size)
+	data.OS = types.StringValue(machine.OS)
+	data.RAM = types.StringValue(string(machine.RAM))
+	data.CPUs = types.Int64Value(int64(machine.CPUs))
+	data.GPU = types.StringValue(machine.GPU)
+	data.StorageTotal = types.StringValue(string(machine.StorageTotal))
+	data.StorageUsed = types.StringValue(string(machine.StorageUsed))
+	data.UsageRate = types.StringValue(machine.UsageRate)
+	data.ShutdownTimeoutForces = types.BoolValue(machine.ShutdownTimeoutForces)
+	data.PerformAutoSnapshot = types.BoolValue(machine.PerformAutoSnapshot)
+	data.AutoSnapshotFrequency = types.StringValue(string(machine.AutoSnapshotFrequency))
+	data.AutoSnapshotSaveCount = types.Int64Value(int64(machine.AutoSnapshotSaveCount))
+	data.AgentType = types.StringValue(machine.AgentType)
+	data.DtCreated = types.StringValue(machine.DtCreated)
+	data.State = types.StringValue(machine.State)
+	data.NetworkID = types.StringValue(machine.NetworkID)
+	data.PrivateIPAddress = types.StringValue(machine.PrivateIpAddress)
+	data.PublicIPAddress = types.StringValue(machine.PublicIpAddress)
+	data.Region = regionStateValue(machine.Region, data.Region)
+	data.UserID = types.StringValue(machine.UserID)
+	data.TeamID = types.StringValue(machine.TeamID)
+	data.ScriptID = optionalStringValue(machine.ScriptID, data.ScriptID)
+	data.DtLastRun = types.StringValue(machine.DtLastRun)
+	data.IsManaged = types.BoolValue(machine.IsManaged)
+
+	// a null timeout means the machine lives forever
+	data.ShutdownTimeoutInHours = types.Int64Null()
+	if machine.ShutdownTimeoutInHours != nil {
+		data.ShutdownTimeoutInHours = types.Int64Value(int64(*machine.ShutdownTimeoutInHours))
+	}
+
+	for powerState, machineState := range machinePowerStates {
+		if machine.State == machineState {
+			data.PowerState = types.StringValue(powerState)
 		}
+	}
+	if data.PowerState.IsUnknown() {
+		data.PowerState = types.StringNull()
+	}
 
-		return err
-	}
-
-	SetResData(d, body, "name")
-	SetResData(d, body, "os")
-	SetResData(d, body, "ram")
-	SetResData(d, body, "cpus")
-	SetResData(d, body, "gpu")
-	SetResDataFrom(d, body, "storage_total", "storageTotal")
-	SetResDataFrom(d, body, "storage_used", "storageUsed")
-	SetResDataFrom(d, body, "usage_rate", "usageRate")
-
-	shutdown_timeout := d.Get("shutdown_timeout_in_hours")
-	_, ok := shutdown_timeout.(int32)
-	if ok {
-		SetResDataFrom(d, body, "shutdown_timeout_in_hours", "shutdownTimeoutInHours")
-	}
-
-	SetResDataFrom(d, body, "shutdown_timeout_forces", "shutdownTimeoutForces")
-	SetResDataFrom(d, body, "perform_auto_snapshot", "performAutoSnapshot")
-	SetResDataFrom(d, body, "auto_snapshot_frequency", "autoSnapshotFrequency")
-	SetResDataFrom(d, body, "auto_snapshot_save_count", "autoSnapshotSaveCount")
-	SetResDataFrom(d, body, "agent_type", "agentType")
-	SetResDataFrom(d, body, "dt_created", "dtCreated")
-	SetResData(d, body, "state")
-	SetResDataFrom(d, body, "network_id", "networkId") //overlays with null initially
-	SetResDataFrom(d, body, "private_ip_address", "privateIpAddress")
-	SetResDataFrom(d, body, "public_ip_address", "publicIpAddress")
-	SetResData(d, body, "region") //overlays with null initially
-	SetResDataFrom(d, body, "user_id", "userId")
-	SetResDataFrom(d, body, "team_id", "teamId")
-	SetResDataFrom(d, body, "script_id", "scriptId")
-	SetResDataFrom(d, body, "dt_last_run", "dtLastRun")
-	SetResDataFrom(d, body, "is_managed", "isManaged")
-
-	return nil
+	return true, diags
 }
 
-func resourceMachineUpdate(d *schema.ResourceData, m interface{}) error {
-	return resourceMachineRead(d, m)
+// machineTypeStateValue returns the machine type, which the API only reports
+// as the first word of the usage rate, e.g. "C2 hourly". prior is kept when
+// it only differs in case.
+func machineTypeStateValue(usageRate string, prior types.String) types.String {
+	machineType, _, _ := strings.Cut(usageRate, " ")
+	if machineType == "" || strings.EqualFold(machineType, prior.ValueString()) {
+		return prior
+	}
+
+	return types.StringValue(machineType)
 }
 
-func resourceMachineDelete(d *schema.ResourceData, m interface{}) error {
-	paperspaceClient := newInternalPaperspaceClient(m)
+// machineSizeStateValue returns the disk size in GB, which the API only
+// reports as storageTotal in bytes.
+func machineSizeStateValue(storageTotal FlexString, prior types.Int64) types.Int64 {
+	bytes, err := strconv.ParseInt(string(storageTotal), 10, 64)
+	if err != nil || bytes <= 0 {
+		return prior
+	}
 
-	err := paperspaceClient.DeleteMachine(d.Id())
-	if err != nil {
-		if err.Error() == MachineDeleteNotFoundError {
-			d.SetId("")
-			return nil
+	return types.Int64Value(bytes / (1 << 30))
+}
+
+// optionalStringValue reads an attribute the API reports as empty when unset
+// as null, unless it was set before.
+func optionalStringValue(v string, prior types.String) types.String {
+	if v == "" && (prior.IsNull() || prior.IsUnknown()) {
+		return types.StringNull()
+	}
+
+	return types.StringValue(v)
+}
+
+func (r *machineResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data machineResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	found, diags := readMachine(ctx, newInternalPaperspaceClient(r.meta), &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if !found {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// changed reports whether an attribute is planned to take a new, known value.
+func changed(plan, state attr.Value) bool {
+	return !plan.IsUnknown() && !plan.Equal(state)
+}
+
+func (r *machineResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state machineResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	timeout, diags := plan.Timeouts.Update(ctx, machineUpdateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	paperspaceClient := newInternalPaperspaceClient(r.meta)
+	id := state.ID.ValueString()
+
+	params := MachineUpdateParams{}
+	if changed(plan.Name, state.Name) {
+		name := plan.Name.ValueString()
+		params.Name = &name
+	}
+	if changed(plan.PerformAutoSnapshot, state.PerformAutoSnapshot) {
+		performAutoSnapshot := plan.PerformAutoSnapshot.ValueBool()
+		params.PerformAutoSnapshot = &performAutoSnapshot
+	}
+	if changed(plan.AutoSnapshotFrequency, state.AutoSnapshotFrequency) {
+		autoSnapshotFrequency := plan.AutoSnapshotFrequency.ValueString()
+		params.AutoSnapshotFrequency = &autoSnapshotFrequency
+	}
+	if changed(plan.AutoSnapshotSaveCount, state.AutoSnapshotSaveCount) {
+		autoSnapshotSaveCount := int(plan.AutoSnapshotSaveCount.ValueInt64())
+		params.AutoSnapshotSaveCount = &autoSnapshotSaveCount
+	}
+	if changed(plan.ShutdownTimeoutInHours, state.ShutdownTimeoutInHours) || changed(plan.LiveForever, state.LiveForever) {
+		params.LiveForever = plan.LiveForever.ValueBool()
+		if !plan.ShutdownTimeoutInHours.IsNull() && !plan.ShutdownTimeoutInHours.IsUnknown() {
+			shutdownTimeoutInHours := int(plan.ShutdownTimeoutInHours.ValueInt64())
+			params.ShutdownTimeoutInHours = &shutdownTimeoutInHours
+		}
+	}
+
+	if params != (MachineUpdateParams{}) {
+		if err := paperspaceClient.UpdateMachine(ctx, id, params); err != nil {
+			resp.Diagnostics.Append(errorDiagnostic(fmt.Sprintf("Error updating paperspace machine %s", id), err))
+			return
 		}
-		return err
 	}
 
-	return resource.Retry(d.Timeout(schema.TimeoutDelete), func() *resource.RetryError {
-		body, err := paperspaceClient.GetMachine(d.Id())
-		log.Printf("\nbody: %v\nerr: %v", body, err)
+	if powerState := plan.PowerState.ValueString(); powerState != "" && changed(plan.PowerState, state.PowerState) {
+		resp.Diagnostics.Append(setMachinePowerState(ctx, paperspaceClient, id, powerState, timeout)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	_, diags = readMachine(ctx, paperspaceClient, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *machineResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data machineResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	timeout, diags := data.Timeouts.Delete(ctx, machineDeleteTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	paperspaceClient := newInternalPaperspaceClient(r.meta)
+	id := data.ID.ValueString()
+
+	if err := paperspaceClient.DeleteMachine(ctx, id); err != nil {
+		if IsNotFound(err) {
+			return
+		}
+		resp.Diagnostics.Append(errorDiagnostic(fmt.Sprintf("Error deleting paperspace machine %s", id), err))
+		return
+	}
+
+	err := retryContext(ctx, timeout, func(ctx context.Context) *retryError {
+		_, err := paperspaceClient.GetMachine(ctx, id)
 		if err != nil {
-			if strings.Contains(err.Error(), "machine not found") {
-				return resource.NonRetryableError(nil)
+			if IsNotFound(err) {
+				return nonRetryableError(nil)
 			}
-			return resource.RetryableError(err)
+			return retryableError(err)
 		}
 
-		return resource.RetryableError(fmt.Errorf("Expected machine to be deleted but still exists"))
+		return retryableError(fmt.Errorf("Expected machine to be deleted but still exists"))
 	})
+	if err != nil {
+		resp.Diagnostics.Append(errorDiagnostic(fmt.Sprintf("Error waiting for paperspace machine %s to be deleted", id), err))
+	}
 }
 
-func resourceMachine() *schema.Resource {
-	return &schema.Resource{
-		Create: resourceMachineCreate,
-		Read:   resourceMachineRead,
-		Update: resourceMachineUpdate,
-		Delete: resourceMachineDelete,
-		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
-		},
+func (r *machineResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
 
-		Schema: map[string]*schema.Schema{
-			"region": {
-				Type:     schema.TypeString,
-				Optional: true,
-				Computed: true,
-			},
-			"machine_type": {
-				Type:     schema.TypeString,
-				Required: true,
-			},
-			"size": {
-				Type:     schema.TypeInt,
-				Required: true,
+// ValidateConfig checks arguments that are only invalid in combination, so
+// mistakes fail at plan time rather than when the API rejects them.
+func (r *machineResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config machineResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// unknown values are checked once they are known, as they may be null
+	if config.LiveForever.ValueBool() && !config.ShutdownTimeoutInHours.IsNull() && !config.ShutdownTimeoutInHours.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(path.Root("shutdown_timeout_in_hours"), "Conflicting paperspace machine arguments",
+			"shutdown_timeout_in_hours cannot be set when live_forever is true, since a machine that lives forever is never shut down.")
+	}
+
+	if !config.PerformAutoSnapshot.IsNull() && !config.PerformAutoSnapshot.IsUnknown() && !config.PerformAutoSnapshot.ValueBool() {
+		snapshotSettings := map[string]attr.Value{
+			"auto_snapshot_frequency":  config.AutoSnapshotFrequency,
+			"auto_snapshot_save_count": config.AutoSnapshotSaveCount,
+		}
+		for attribute, value := range snapshotSettings {
+			if !value.IsNull() && !value.IsUnknown() {
+				resp.Diagnostics.AddAttributeError(path.Root(attribute), "Conflicting paperspace machine arguments",
+					fmt.Sprintf("%s cannot be set when perform_auto_snapshot is false.", attribute))
+			}
+		}
+	}
+}
+
+// ModifyPlan ignores changes that only respell the region, drops the
+// shutdown timeout of machines that live forever, and checks machine_type
+// against the machine types offered in the machine's region whenever it is
+// set or changed.
+func (r *machineResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	suppressEquivalentPlan(ctx, req, resp)
+
+	var liveForever types.Bool
+	var shutdownTimeoutInHours types.Int64
+	resp.Diagnostics.Append(resp.Plan.GetAttribute(ctx, path.Root("live_forever"), &liveForever)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("shutdown_timeout_in_hours"), &shutdownTimeoutInHours)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if liveForever.ValueBool() && shutdownTimeoutInHours.IsNull() {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("shutdown_timeout_in_hours"), types.Int64Null())...)
+	}
+
+	config, ok := r.meta.(ClientConfig)
+	if !ok {
+		return
+	}
+
+	var plan machineResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() || plan.MachineType.IsUnknown() {
+		return
+	}
+
+	if !req.State.Raw.IsNull() {
+		var state machineResourceModel
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+		if resp.Diagnostics.HasError() || state.MachineType.Equal(plan.MachineType) {
+			return
+		}
+	}
+
+	// region falls back to the provider's, as in Create
+	region := config.Region
+	if v := plan.Region.ValueString(); v != "" {
+		region = v
+	}
+	if region == "" {
+		return
+	}
+
+	resp.Diagnostics.Append(validateMachineType(ctx, config, regionName(region), plan.MachineType.ValueString())...)
+}
+
+// machineSDKOptionalAttributes are the optional, non-computed attributes the
+// SDK based releases stored as "" or false when unset.
+var machineSDKOptionalAttributes = []string{
+	"assign_public_ip",
+	"email",
+	"password",
+	"firstname",
+	"lastname",
+	"notification_email",
+	"script_id",
+	"live_forever",
+}
+
+// UpgradeState migrates state written by the SDK based releases: version 0
+// stored auto_snapshot_save_count as a string, and both versions stored unset
+// optional attributes as zero values rather than null.
+func (r *machineResource) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		0: {
+			StateUpgrader: func(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+				upgradeStateJSON(ctx, req, resp, func(attributes map[string]interface{}) error {
+					zeroToNull(attributes, machineSDKOptionalAttributes...)
+					return stringToInt64(attributes, "auto_snapshot_save_count")
+				})
 			},
-			"billing_type": {
-				Type:     schema.TypeString,
-				Required: true,
+		},
+		1: {
+			StateUpgrader: func(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+				upgradeStateJSON(ctx, req, resp, func(attributes map[string]interface{}) error {
+					zeroToNull(attributes, machineSDKOptionalAttributes...)
+					return nil
+				})
 			},
-			"name": {
-				Type:     schema.TypeString,
+		},
+	}
+}
+
+func (r *machineResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Version: 2,
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:      true,
+				PlanModifiers: []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
+			},
+			"region": schema.StringAttribute{
+				Optional:   true,
+				Computed:   true,
+				Validators: []validator.String{regionValidator{}},
+				PlanModifiers: []planmodifier.String{
+					regionPlanModifier{},
+					stringplanmodifier.RequiresReplace(),
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			// the API cannot resize machines
+			"machine_type": schema.StringAttribute{
+				Required:      true,
+				Validators:    []validator.String{stringvalidator.LengthAtLeast(1)},
+				PlanModifiers: []planmodifier.String{stringplanmodifier.RequiresReplace()},
+			},
+			"size": schema.Int64Attribute{
+				Required:      true,
+				Validators:    []validator.Int64{int64validator.AtLeast(1)},
+				PlanModifiers: []planmodifier.Int64{int64planmodifier.RequiresReplace()},
+			},
+			"billing_type": schema.StringAttribute{
+				Required:      true,
+				Validators:    []validator.String{stringvalidator.OneOf("hourly", "monthly")},
+				PlanModifiers: []planmodifier.String{stringplanmodifier.RequiresReplace()},
+			},
+			"name": schema.StringAttribute{
 				Required: true,
 			},
-			"template_id": {
-				Type:     schema.TypeString,
-				Required: true,
+			"template_id": schema.StringAttribute{
+				Required:      true,
+				PlanModifiers: []planmodifier.String{stringplanmodifier.RequiresReplace()},
 			},
-			"assign_public_ip": {
-				Type:     schema.TypeBool,
-				Optional: true,
+			// the arguments below up to script_id are only read by the API when
+			// the machine is created
+			"assign_public_ip": schema.BoolAttribute{
+				Optional:      true,
+				PlanModifiers: []planmodifier.Bool{boolRequiresReplaceUnlessUnset()},
 			},
-			"network_id": {
-				Type:     schema.TypeString,
+			"network_id": schema.StringAttribute{
 				Optional: true,
 				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
 			},
-			"team_id": {
-				Type:     schema.TypeString,
+			"team_id": schema.StringAttribute{
 				Optional: true,
 				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
 			},
-			"user_id": {
-				Type:     schema.TypeString,
+			"user_id": schema.StringAttribute{
 				Optional: true,
 				Computed: true,
-			},
-			"email": {
-				Type:     schema.TypeString,
-				Optional: true,
-			},
-			"password": {
-				Type:     schema.TypeString,
-				Optional: true,
-			},
-			"firstname": {
-				Type:     schema.TypeString,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"email": schema.StringAttribute{
+				Optional:      true,
+				PlanModifiers: []planmodifier.String{stringRequiresReplaceUnlessUnset()},
+			},
+			"password": schema.StringAttribute{
+				Optional:      true,
+				Sensitive:     true,
+				PlanModifiers: []planmodifier.String{stringRequiresReplaceUnlessUnset()},
+			},
+			"firstname": schema.StringAttribute{
+				Optional:      true,
+				PlanModifiers: []planmodifier.String{stringRequiresReplaceUnlessUnset()},
+			},
+			"lastname": schema.StringAttribute{
+				Optional:      true,
+				PlanModifiers: []planmodifier.String{stringRequiresReplaceUnlessUnset()},
+			},
+			"notification_email": schema.StringAttribute{
+				Optional:      true,
+				PlanModifiers: []planmodifier.String{stringRequiresReplaceUnlessUnset()},
+			},
+			// computed so that a script attached by a
+			// paperspace_machine_script_attachment is not a change
+			"script_id": schema.StringAttribute{
 				Optional: true,
-			},
-			"lastname": {
-				Type:     schema.TypeString,
-				Optional: true,
-			},
-			"notification_email": {
-				Type:     schema.TypeString,
-				Optional: true,
-			},
-			"script_id": {
-				Type:     schema.TypeString,
-				Optional: true,
-			},
-			"dt_last_run": {
-				Type:     schema.TypeString,
 				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringRequiresReplaceUnlessUnset(),
+				},
 			},
-			"os": {
-				Type:     schema.TypeString,
+			"dt_last_run": schema.StringAttribute{
 				Computed: true,
 			},
-			"ram": {
-				Type:     schema.TypeString,
+			"os": schema.StringAttribute{
+				Computed:      true,
+				PlanModifiers: []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
+			},
+			"ram": schema.StringAttribute{
 				Computed: true,
 			},
-			"cpus": {
-				Type:     schema.TypeInt,
+			"cpus": schema.Int64Attribute{
 				Computed: true,
 			},
-			"gpu": {
-				Type:     schema.TypeString,
+			"gpu": schema.StringAttribute{
 				Computed: true,
 			},
-			"storage_total": {
-				Type:     schema.TypeString,
+			"storage_total": schema.StringAttribute{
 				Computed: true,
 			},
-			"storage_used": {
-				Type:     schema.TypeString,
+			"storage_used": schema.StringAttribute{
 				Computed: true,
 			},
-			"usage_rate": {
-				Type:     schema.TypeString,
+			"usage_rate": schema.StringAttribute{
 				Computed: true,
 			},
-			"shutdown_timeout_in_hours": {
-				Type:     schema.TypeInt,
-				Optional: true,
+			"shutdown_timeout_in_hours": schema.Int64Attribute{
+				Optional:      true,
+				Computed:      true,
+				Validators:    []validator.Int64{int64validator.Between(1, machineMaxShutdownTimeoutInHours)},
+				PlanModifiers: []planmodifier.Int64{int64planmodifier.UseStateForUnknown()},
 			},
-			"shutdown_timeout_forces": {
-				Type:     schema.TypeBool,
+			"shutdown_timeout_forces": schema.BoolAttribute{
 				Computed: true,
 			},
-			"perform_auto_snapshot": {
-				Type:     schema.TypeBool,
-				Optional: true,
-				Computed: true,
+			"perform_auto_snapshot": schema.BoolAttribute{
+				Optional:      true,
+				Computed:      true,
+				PlanModifiers: []planmodifier.Bool{boolplanmodifier.UseStateForUnknown()},
 			},
-			"auto_snapshot_frequency": {
-				Type:     schema.TypeString,
-				Optional: true,
-				Computed: true,
+			"auto_snapshot_frequency": schema.StringAttribute{
+				Optional:      true,
+				Computed:      true,
+				Validators:    []validator.String{stringvalidator.OneOf("hour", "day", "week")},
+				PlanModifiers: []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
 			},
-			"auto_snapshot_save_count": {
-				Type:     schema.TypeString,
-				Optional: true,
-				Computed: true,
+			"auto_snapshot_save_count": schema.Int64Attribute{
+				Optional:      true,
+				Computed:      true,
+				Validators:    []validator.Int64{int64validator.AtLeast(1)},
+				PlanModifiers: []planmodifier.Int64{int64planmodifier.UseStateForUnknown()},
 			},
-			"agent_type": {
-				Type:     schema.TypeString,
-				Computed: true,
+			"agent_type": schema.StringAttribute{
+				Computed:      true,
+				PlanModifiers: []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
 			},
-			"dt_created": {
-				Type:     schema.TypeString,
-				Computed: true,
+			"dt_created": schema.StringAttribute{
+				Computed:      true,
+				PlanModifiers: []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
 			},
-			"state": {
-				Type:     schema.TypeString,
+			"state": schema.StringAttribute{
 				Computed: true,
 			},
-			"private_ip_address": {
-				Type:     schema.TypeString,
+			"power_state": schema.StringAttribute{
+				Optional:      true,
+				Computed:      true,
+				Validators:    []validator.String{stringvalidator.OneOf("running", "off")},
+				PlanModifiers: []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
+			},
+			"private_ip_address": schema.StringAttribute{
 				Computed: true,
 			},
-			"public_ip_address": {
-				Type:     schema.TypeString,
+			"public_ip_address": schema.StringAttribute{
 				Computed: true,
 			},
-			"live_forever": {
-				Type:     schema.TypeBool,
+			"live_forever": schema.BoolAttribute{
 				Optional: true,
 			},
-			"is_managed": {
-				Type:     schema.TypeBool,
+			// computed since the API reports it even when not configured; like
+			// the arguments above, it is only read when the machine is created
+			"is_managed": schema.BoolAttribute{
 				Optional: true,
+				Computed: true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+					boolplanmodifier.RequiresReplace(),
+				},
 			},
 		},
-		Timeouts: &schema.ResourceTimeout{
-			Create: schema.DefaultTimeout(10 * time.Minute),
-			Delete: schema.DefaultTimeout(5 * time.Minute),
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Update: true,
+				Delete: true,
+			}),
 		},
 	}
 }
@@ -0,0 +1,234 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// machineScriptAttachmentResource sets the startup script of a machine it
+// does not manage. Its id is the machine id: a machine has one script.
+type machineScriptAttachmentResource struct {
+	meta interface{}
+}
+
+type machineScriptAttachmentResourceModel struct {
+	ID        types.String `tfsdk:"id"`
+	MachineID types.String `tfsdk:"machine_id"`
+	ScriptID  types.String `tfsdk:"script_id"`
+}
+
+func resourceMachineScriptAttachment() resource.Resource {
+	return &machineScriptAttachmentResource{}
+}
+
+func (r *machineScriptAttachmentResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_machine_script_attachment"
+}
+
+func (r *machineScriptAttachmentResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	r.meta = req.ProviderData
+}
+
+// readMachineScriptAttachment refreshes data from the machine, returning
+// false if the machine is gone or no longer has the attached script. An
+// imported attachment, which has no script_id yet, adopts the machine's.
+func readMachineScriptAttachment(ctx context.Context, paperspaceClient PaperspaceClient, data *machineScriptAttachmentResourceModel) (bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	machineID := data.MachineID.ValueString()
+
+	machine, err := paperspaceClient.GetMachine(ctx, machineID)
+	if err != nil {
+		if IsNotFound(err) {
+			log.Printf("[INFO] paperspace resourceMachineScriptAttachmentRead machine %s not found", machineID)
+			return false, diags
+		}
+
+		diags.Append(errorDiagnostic(fmt.Sprintf("Error reading paperspace machine %s", machineID), err))
+		return false, diags
+	}
+
+	if machine.ScriptID == "" {
+		log.Printf("[INFO] paperspace resourceMachineScriptAttachmentRead machine %s has no script", machineID)
+		return false, diags
+	}
+	if !data.ScriptID.IsNull() && machine.ScriptID != data.ScriptID.ValueString() {
+		log.Printf("[INFO] paperspace resourceMachineScriptAttachmentRead machine %s has script %q attached some other way", machineID, machine.ScriptID)
+		return false, diags
+	}
+
+	data.ID = types.StringValue(machine.ID)
+	data.ScriptID = types.StringValue(machine.ScriptID)
+
+	return true, diags
+}
+
+func (r *machineScriptAttachmentResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan machineScriptAttachmentResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	paperspaceClient := newInternalPaperspaceClient(r.meta)
+	machineID := plan.MachineID.ValueString()
+
+	// never take over a script attached some other way
+	machine, err := paperspaceClient.GetMachine(ctx, machineID)
+	if err != nil {
+		resp.Diagnostics.Append(attributeErrorDiagnostic(path.Root("machine_id"), fmt.Sprintf("Error reading paperspace machine %s", machineID), err))
+		return
+	}
+	if machine.ScriptID != "" {
+		resp.Diagnostics.AddAttributeError(path.Root("machine_id"), "Paperspace machine already has a script",
+			fmt.Sprintf("Machine %s already has script %s attached. Import the attachment to manage it:\n\n"+
+				"  terraform import <address of this resource> %s\n\nor detach the script from the machine first.", machineID, machine.ScriptID, machineID))
+		return
+	}
+
+	if err := paperspaceClient.SetMachineScript(ctx, machineID, plan.ScriptID.ValueString()); err != nil {
+		resp.Diagnostics.Append(errorDiagnostic(fmt.Sprintf("Error attaching script to paperspace machine %s", machineID), err))
+		return
+	}
+
+	found, diags := readMachineScriptAttachment(ctx, paperspaceClient, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if !found {
+		resp.Diagnostics.AddError("Error reading paperspace machine script attachment", fmt.Sprintf("machine %s has no script after attaching %s", machineID, plan.ScriptID.ValueString()))
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *machineScriptAttachmentResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data machineScriptAttachmentResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	found, diags := readMachineScriptAttachment(ctx, newInternalPaperspaceClient(r.meta), &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if !found {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *machineScriptAttachmentResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state machineScriptAttachmentResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	paperspaceClient := newInternalPaperspaceClient(r.meta)
+	machineID := plan.MachineID.ValueString()
+
+	// only swap out the script this attachment put there
+	machine, err := paperspaceClient.GetMachine(ctx, machineID)
+	if err != nil {
+		resp.Diagnostics.Append(attributeErrorDiagnostic(path.Root("machine_id"), fmt.Sprintf("Error reading paperspace machine %s", machineID), err))
+		return
+	}
+	if machine.ScriptID != state.ScriptID.ValueString() {
+		resp.Diagnostics.AddAttributeError(path.Root("script_id"), "Paperspace machine script changed outside of this attachment",
+			fmt.Sprintf("Machine %s has script %q attached, not %s. Refresh the attachment to adopt the machine's script, or detach it first.", machineID, machine.ScriptID, state.ScriptID.ValueString()))
+		return
+	}
+
+	if err := paperspaceClient.SetMachineScript(ctx, machineID, plan.ScriptID.ValueString()); err != nil {
+		resp.Diagnostics.Append(errorDiagnostic(fmt.Sprintf("Error attaching script to paperspace machine %s", machineID), err))
+		return
+	}
+
+	found, diags := readMachineScriptAttachment(ctx, paperspaceClient, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if !found {
+		resp.Diagnostics.AddError("Error reading paperspace machine script attachment", fmt.Sprintf("machine %s has no script after attaching %s", machineID, plan.ScriptID.ValueString()))
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *machineScriptAttachmentResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data machineScriptAttachmentResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	paperspaceClient := newInternalPaperspaceClient(r.meta)
+	machineID := data.MachineID.ValueString()
+
+	machine, err := paperspaceClient.GetMachine(ctx, machineID)
+	if err != nil {
+		if IsNotFound(err) {
+			log.Printf("[INFO] paperspace resourceMachineScriptAttachmentDelete machine %s already deleted", machineID)
+			return
+		}
+
+		resp.Diagnostics.Append(errorDiagnostic(fmt.Sprintf("Error reading paperspace machine %s", machineID), err))
+		return
+	}
+
+	// leave a script attached by someone else in place
+	if machine.ScriptID != data.ScriptID.ValueString() {
+		log.Printf("[INFO] paperspace resourceMachineScriptAttachmentDelete machine %s has script %q, not detaching", machineID, machine.ScriptID)
+		return
+	}
+
+	if err := paperspaceClient.SetMachineScript(ctx, machineID, ""); err != nil {
+		resp.Diagnostics.Append(errorDiagnostic(fmt.Sprintf("Error detaching script from paperspace machine %s", machineID), err))
+		return
+	}
+}
+
+func (r *machineScriptAttachmentResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("machine_id"), req.ID)...)
+}
+
+func (r *machineScriptAttachmentResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:      true,
+				PlanModifiers: []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
+			},
+			"machine_id": schema.StringAttribute{
+				Required:      true,
+				Validators:    []validator.String{stringvalidator.LengthAtLeast(1)},
+				PlanModifiers: []planmodifier.String{stringplanmodifier.RequiresReplace()},
+			},
+			"script_id": schema.StringAttribute{
+				Required:   true,
+				Validators: []validator.String{stringvalidator.LengthAtLeast(1)},
+			},
+		},
+	}
+}
@@ -0,0 +1,196 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestAccMachineScriptAttachment_basic(t *testing.T) {
+	api := newFakeAPI(t)
+	config := api.providerConfig() + `
+resource "paperspace_machine" "test" {
+  name         = "tf-acc-machine"
+  machine_type = "C2"
+  size         = 50
+  billing_type = "hourly"
+  template_id  = "tubuntu1"
+}
+
+resource "paperspace_script" "a" {
+  name        = "tf-acc-script-a"
+  script_text = "#!/bin/bash\necho a"
+}
+
+resource "paperspace_script" "b" {
+  name        = "tf-acc-script-b"
+  script_text = "#!/bin/bash\necho b"
+}
+`
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories(),
+		CheckDestroy:             testAccCheckMachineDestroy(api),
+		Steps: []resource.TestStep{
+			{
+				Config: config + `
+resource "paperspace_machine_script_attachment" "test" {
+  machine_id = paperspace_machine.test.id
+  script_id  = paperspace_script.a.id
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair("paperspace_machine_script_attachment.test", "id", "paperspace_machine.test", "id"),
+					resource.TestCheckResourceAttrPair("paperspace_machine_script_attachment.test", "script_id", "paperspace_script.a", "id"),
+					testAccCheckFakeMachineScript(api, "paperspace_machine.test", "paperspace_script.a"),
+				),
+			},
+			{
+				Config: config + `
+resource "paperspace_machine_script_attachment" "test" {
+  machine_id = paperspace_machine.test.id
+  script_id  = paperspace_script.b.id
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair("paperspace_machine_script_attachment.test", "script_id", "paperspace_script.b", "id"),
+					testAccCheckFakeMachineScript(api, "paperspace_machine.test", "paperspace_script.b"),
+				),
+			},
+			{
+				ResourceName:      "paperspace_machine_script_attachment.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				Config: config,
+				Check:  testAccCheckFakeMachineField(api, "paperspace_machine.test", "scriptId", ""),
+			},
+		},
+	})
+}
+
+// TestAccMachineScriptAttachment_alreadyAttached checks that an attachment
+// does not silently replace a script attached some other way.
+func TestAccMachineScriptAttachment_alreadyAttached(t *testing.T) {
+	api := newFakeAPI(t)
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories(),
+		CheckDestroy:             testAccCheckMachineDestroy(api),
+		Steps: []resource.TestStep{
+			{
+				Config: api.providerConfig() + `
+resource "paperspace_script" "a" {
+  name        = "tf-acc-script-a"
+  script_text = "#!/bin/bash\necho a"
+}
+
+resource "paperspace_script" "b" {
+  name        = "tf-acc-script-b"
+  script_text = "#!/bin/bash\necho b"
+}
+
+resource "paperspace_machine" "test" {
+  name         = "tf-acc-machine"
+  machine_type = "C2"
+  size         = 50
+  billing_type = "hourly"
+  template_id  = "tubuntu1"
+  script_id    = paperspace_script.a.id
+}
+
+resource "paperspace_machine_script_attachment" "test" {
+  machine_id = paperspace_machine.test.id
+  script_id  = paperspace_script.b.id
+}
+`,
+				ExpectError: regexp.MustCompile(`(?s)Paperspace machine already has a script.*terraform import`),
+			},
+		},
+	})
+}
+
+// TestAccMachineScriptAttachment_replacedOutside checks that a script
+// attached to the machine some other way is neither adopted nor overwritten.
+func TestAccMachineScriptAttachment_replacedOutside(t *testing.T) {
+	api := newFakeAPI(t)
+	config := api.providerConfig() + `
+resource "paperspace_machine" "test" {
+  name         = "tf-acc-machine"
+  machine_type = "C2"
+  size         = 50
+  billing_type = "hourly"
+  template_id  = "tubuntu1"
+}
+
+resource "paperspace_script" "a" {
+  name        = "tf-acc-script-a"
+  script_text = "#!/bin/bash\necho a"
+}
+
+resource "paperspace_machine_script_attachment" "test" {
+  machine_id = paperspace_machine.test.id
+  script_id  = paperspace_script.a.id
+}
+`
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories(),
+		CheckDestroy:             testAccCheckMachineDestroy(api),
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check:  testAccCheckFakeMachineScript(api, "paperspace_machine.test", "paperspace_script.a"),
+			},
+			{
+				PreConfig: func() {
+					api.mu.Lock()
+					defer api.mu.Unlock()
+
+					for _, machine := range api.machines {
+						machine.fields["scriptId"] = "sother"
+					}
+				},
+				Config:      config,
+				ExpectError: regexp.MustCompile(`(?s)Paperspace machine already has a script.*script sother attached`),
+			},
+			{
+				Config: api.providerConfig() + `
+resource "paperspace_machine" "test" {
+  name         = "tf-acc-machine"
+  machine_type = "C2"
+  size         = 50
+  billing_type = "hourly"
+  template_id  = "tubuntu1"
+}
+
+resource "paperspace_script" "a" {
+  name        = "tf-acc-script-a"
+  script_text = "#!/bin/bash\necho a"
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckFakeMachineField(api, "paperspace_machine.test", "scriptId", "sother"),
+					resource.TestCheckResourceAttr("paperspace_machine.test", "script_id", "sother"),
+				),
+			},
+		},
+	})
+}
+
+// testAccCheckFakeMachineScript checks that the fake machine runs the script
+// managed as script.
+func testAccCheckFakeMachineScript(api *fakeAPI, machine, script string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[script]
+		if !ok {
+			return fmt.Errorf("Not found: %s", script)
+		}
+
+		return testAccCheckFakeMachineField(api, machine, "scriptId", rs.Primary.ID)(s)
+	}
+}
@@ -0,0 +1,545 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+)
+
+func TestAccMachine_basic(t *testing.T) {
+	api := newFakeAPI(t)
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories(),
+		CheckDestroy:             testAccCheckMachineDestroy(api),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccMachineConfig(api, "tf-acc-machine", "P4000", 50, "running"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMachineExists(api, "paperspace_machine.test"),
+					resource.TestCheckResourceAttr("paperspace_machine.test", "name", "tf-acc-machine"),
+					resource.TestCheckResourceAttr("paperspace_machine.test", "state", "ready"),
+					resource.TestCheckResourceAttr("paperspace_machine.test", "power_state", "running"),
+					resource.TestCheckResourceAttr("paperspace_machine.test", "region", fakeRegion),
+					resource.TestCheckResourceAttr("paperspace_machine.test", "cpus", "8"),
+					resource.TestCheckResourceAttr("paperspace_machine.test", "storage_total", "53687091200"),
+					resource.TestCheckResourceAttr("paperspace_machine.test", "shutdown_timeout_in_hours", "24"),
+					resource.TestCheckResourceAttrSet("paperspace_machine.test", "public_ip_address"),
+				),
+			},
+			{
+				Config: testAccMachineConfig(api, "tf-acc-machine-renamed", "P4000", 50, "off"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("paperspace_machine.test", "name", "tf-acc-machine-renamed"),
+					resource.TestCheckResourceAttr("paperspace_machine.test", "state", "off"),
+					resource.TestCheckResourceAttr("paperspace_machine.test", "power_state", "off"),
+				),
+			},
+			{
+				Config:            testAccMachineConfig(api, "tf-acc-machine-renamed", "P4000", 50, "off"),
+				ResourceName:      "paperspace_machine.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+				// write-only or create-only arguments the API does not return
+				ImportStateVerifyIgnore: []string{"assign_public_ip", "billing_type", "template_id", "live_forever"},
+			},
+		},
+	})
+}
+
+// TestAccMachine_resize checks that changing machine_type or size replaces
+// the machine, as the API cannot resize machines, and that both are read back
+// so resizes made outside Terraform show up as a diff.
+func TestAccMachine_resize(t *testing.T) {
+	api := newFakeAPI(t)
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories(),
+		CheckDestroy:             testAccCheckMachineDestroy(api),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccMachineConfig(api, "tf-acc-machine", "P4000", 50, "running"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMachineExists(api, "paperspace_machine.test"),
+					resource.TestCheckResourceAttr("paperspace_machine.test", "machine_type", "P4000"),
+					resource.TestCheckResourceAttr("paperspace_machine.test", "size", "50"),
+				),
+			},
+			{
+				Config: testAccMachineConfig(api, "tf-acc-machine", "P5000", 100, "running"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("paperspace_machine.test", plancheck.ResourceActionDestroyBeforeCreate),
+					},
+				},
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("paperspace_machine.test", "storage_total", "107374182400"),
+					resource.TestCheckResourceAttr("paperspace_machine.test", "state", "ready"),
+					testAccCheckFakeMachineField(api, "paperspace_machine.test", "usageRate", "P5000 hourly"),
+				),
+			},
+			{
+				PreConfig: func() {
+					api.mu.Lock()
+					defer api.mu.Unlock()
+
+					for _, machine := range api.machines {
+						machine.fields["usageRate"] = "P6000 hourly"
+						machine.fields["storageTotal"] = "214748364800"
+					}
+				},
+				Config: testAccMachineConfig(api, "tf-acc-machine", "P5000", 100, "running"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("paperspace_machine.test", plancheck.ResourceActionDestroyBeforeCreate),
+						plancheck.ExpectKnownValue("paperspace_machine.test", tfjsonpath.New("machine_type"), knownvalue.StringExact("P5000")),
+					},
+				},
+				Check: testAccCheckFakeMachineField(api, "paperspace_machine.test", "usageRate", "P5000 hourly"),
+			},
+		},
+	})
+}
+
+// TestAccMachine_createOnlyArguments checks that changing arguments the API
+// only reads at create time replaces the machine, while setting them to
+// their unset value does not.
+func TestAccMachine_createOnlyArguments(t *testing.T) {
+	api := newFakeAPI(t)
+
+	config := func(arguments string) string {
+		return api.providerConfig() + fmt.Sprintf(`
+resource "paperspace_machine" "test" {
+  name         = "tf-acc-machine"
+  machine_type = "C2"
+  size         = 50
+  billing_type = "hourly"
+  template_id  = "tubuntu1"
+%s
+}
+`, arguments)
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories(),
+		CheckDestroy:             testAccCheckMachineDestroy(api),
+		Steps: []resource.TestStep{
+			{
+				Config: config(""),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMachineExists(api, "paperspace_machine.test"),
+					resource.TestCheckResourceAttr("paperspace_machine.test", "public_ip_address", ""),
+				),
+			},
+			{
+				Config: config(`
+  assign_public_ip = false
+  email            = ""
+`),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("paperspace_machine.test", plancheck.ResourceActionUpdate),
+					},
+				},
+			},
+			{
+				Config: config(`
+  assign_public_ip = true
+`),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("paperspace_machine.test", plancheck.ResourceActionDestroyBeforeCreate),
+					},
+				},
+				Check: resource.TestCheckResourceAttrSet("paperspace_machine.test", "public_ip_address"),
+			},
+			{
+				Config: config(`
+  assign_public_ip = true
+  email            = "someone@example.com"
+`),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("paperspace_machine.test", plancheck.ResourceActionDestroyBeforeCreate),
+					},
+				},
+			},
+		},
+	})
+}
+
+// TestAccMachine_liveForever checks that machines can be created to live
+// forever and switched to and from living forever.
+func TestAccMachine_liveForever(t *testing.T) {
+	api := newFakeAPI(t)
+
+	config := func(arguments string) string {
+		return api.providerConfig() + fmt.Sprintf(`
+resource "paperspace_machine" "test" {
+  name         = "tf-acc-machine"
+  machine_type = "C2"
+  size         = 50
+  billing_type = "hourly"
+  template_id  = "tubuntu1"
+%s
+}
+`, arguments)
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories(),
+		CheckDestroy:             testAccCheckMachineDestroy(api),
+		Steps: []resource.TestStep{
+			{
+				Config: config(`
+  live_forever = true
+`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMachineExists(api, "paperspace_machine.test"),
+					testAccCheckFakeMachineField(api, "paperspace_machine.test", "shutdownTimeoutInHours", "<nil>"),
+					resource.TestCheckNoResourceAttr("paperspace_machine.test", "shutdown_timeout_in_hours"),
+				),
+			},
+			{
+				Config: config(`
+  shutdown_timeout_in_hours = 8
+`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckFakeMachineField(api, "paperspace_machine.test", "shutdownTimeoutInHours", "8"),
+					resource.TestCheckResourceAttr("paperspace_machine.test", "shutdown_timeout_in_hours", "8"),
+				),
+			},
+			{
+				Config: config(`
+  live_forever = true
+`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckFakeMachineField(api, "paperspace_machine.test", "shutdownTimeoutInHours", "<nil>"),
+					resource.TestCheckNoResourceAttr("paperspace_machine.test", "shutdown_timeout_in_hours"),
+				),
+			},
+		},
+	})
+}
+
+// TestAccMachine_upgradeFromSDK checks that state written by the SDK based
+// releases plans no changes once upgraded.
+func TestAccMachine_upgradeFromSDK(t *testing.T) {
+	api := newFakeAPI(t)
+	tempDir := testAccTempDir(t)
+
+	config := api.providerConfig() + `
+resource "paperspace_machine" "test" {
+  name                     = "tf-acc-machine"
+  machine_type             = "C2"
+  size                     = 50
+  billing_type             = "hourly"
+  template_id              = "tubuntu1"
+  auto_snapshot_save_count = 3
+}
+`
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories(),
+		CheckDestroy:             testAccCheckMachineDestroy(api),
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check:  testAccCheckMachineExists(api, "paperspace_machine.test"),
+			},
+			{
+				PreConfig: testAccRewriteState(t, tempDir, "paperspace_machine", 0, map[string]interface{}{
+					"assign_public_ip":         false,
+					"email":                    "",
+					"password":                 "",
+					"firstname":                "",
+					"lastname":                 "",
+					"notification_email":       "",
+					"script_id":                "",
+					"live_forever":             false,
+					"auto_snapshot_save_count": "3",
+				}),
+				Config: config,
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{plancheck.ExpectEmptyPlan()},
+				},
+			},
+		},
+	})
+}
+
+// TestAccMachine_regionCode checks that region codes are accepted and stay
+// in state even though the API reports region names.
+func TestAccMachine_regionCode(t *testing.T) {
+	api := newFakeAPI(t)
+
+	config := func(region string) string {
+		return api.providerConfig() + fmt.Sprintf(`
+resource "paperspace_machine" "test" {
+  name         = "tf-acc-machine"
+  region       = %q
+  machine_type = "C2"
+  size         = 50
+  billing_type = "hourly"
+  template_id  = "tubuntu1"
+}
+`, region)
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories(),
+		CheckDestroy:             testAccCheckMachineDestroy(api),
+		Steps: []resource.TestStep{
+			{
+				Config: config("ny2"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("paperspace_machine.test", "region", "ny2"),
+					testAccCheckFakeMachineField(api, "paperspace_machine.test", "region", fakeRegion),
+				),
+			},
+			{
+				Config:   config("East Coast (NY2)"),
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+// TestAccMachine_validation checks that invalid arguments fail at plan time,
+// before any request reaches the API.
+func TestAccMachine_validation(t *testing.T) {
+	api := newFakeAPI(t)
+
+	config := func(arguments string) string {
+		return api.providerConfig() + fmt.Sprintf(`
+resource "paperspace_machine" "test" {
+  name         = "tf-acc-machine"
+  machine_type = "C2"
+  template_id  = "tubuntu1"
+%s
+}
+`, arguments)
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: config(`
+  size         = 50
+  billing_type = "daily"
+`),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`(?s)billing_type.*value must be one of`),
+			},
+			{
+				Config: config(`
+  size         = 0
+  billing_type = "hourly"
+`),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`(?s)size.*must be at least 1`),
+			},
+			{
+				Config: config(`
+  size         = 50
+  billing_type = "hourly"
+  region       = "Moon (LUNA1)"
+`),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`(?s)Invalid paperspace region.*Moon \(LUNA1\)`),
+			},
+			{
+				Config: config(`
+  size                      = 50
+  billing_type              = "hourly"
+  live_forever              = true
+  shutdown_timeout_in_hours = 8
+`),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`(?s)Conflicting paperspace machine arguments.*shutdown_timeout_in_hours cannot be set`),
+			},
+			{
+				Config: config(`
+  size                    = 50
+  billing_type            = "hourly"
+  perform_auto_snapshot   = false
+  auto_snapshot_frequency = "day"
+`),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`(?s)Conflicting paperspace machine arguments.*auto_snapshot_frequency cannot be set`),
+			},
+			// values only known at apply time may still turn out null
+			{
+				Config: api.providerConfig() + `
+resource "terraform_data" "unset" {
+  input = null
+}
+
+resource "paperspace_machine" "test" {
+  name                      = "tf-acc-machine"
+  machine_type              = "C2"
+  template_id               = "tubuntu1"
+  size                      = 50
+  billing_type              = "hourly"
+  live_forever              = true
+  shutdown_timeout_in_hours = terraform_data.unset.output
+  perform_auto_snapshot     = false
+  auto_snapshot_frequency   = terraform_data.unset.output
+  auto_snapshot_save_count  = terraform_data.unset.output
+}
+`,
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+
+	api.mu.Lock()
+	defer api.mu.Unlock()
+	if len(api.machines) != 0 {
+		t.Fatalf("expected no machines to be created, got %d", len(api.machines))
+	}
+}
+
+// TestAccMachine_unknownMachineType checks that machine types missing from
+// the region's catalog fail at plan time.
+func TestAccMachine_unknownMachineType(t *testing.T) {
+	api := newFakeAPI(t)
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories(),
+		CheckDestroy:             testAccCheckMachineDestroy(api),
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccMachineConfig(api, "tf-acc-machine", "P4OOO", 50, "running"),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`(?s)Invalid paperspace machine type.*"P4OOO" is not offered in region.*A4000, C2, P4000, P5000`),
+			},
+			{
+				Config: testAccMachineConfig(api, "tf-acc-machine", "P4000", 50, "running"),
+				Check:  testAccCheckMachineExists(api, "paperspace_machine.test"),
+			},
+			{
+				Config:      testAccMachineConfig(api, "tf-acc-machine", "P5001", 50, "running"),
+				ExpectError: regexp.MustCompile(`(?s)Invalid paperspace machine type.*"P5001"`),
+			},
+		},
+	})
+}
+
+func testAccMachineConfig(api *fakeAPI, name, machineType string, size int, powerState string) string {
+	return api.providerConfig() + fmt.Sprintf(`
+resource "paperspace_machine" "test" {
+  name             = %q
+  machine_type     = %q
+  size             = %d
+  billing_type     = "hourly"
+  template_id      = "tubuntu1"
+  assign_public_ip = true
+  power_state      = %q
+}
+`, name, machineType, size, powerState)
+}
+
+func testAccCheckMachineExists(api *fakeAPI, name string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("Not found: %s", name)
+		}
+		if !api.machineExists(rs.Primary.ID) {
+			return fmt.Errorf("Machine %s does not exist", rs.Primary.ID)
+		}
+
+		return nil
+	}
+}
+
+func testAccCheckFakeMachineField(api *fakeAPI, name, field, expected string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("Not found: %s", name)
+		}
+
+		api.mu.Lock()
+		defer api.mu.Unlock()
+
+		machine, ok := api.machines[rs.Primary.ID]
+		if !ok {
+			return fmt.Errorf("Machine %s does not exist", rs.Primary.ID)
+		}
+		if actual := fmt.Sprint(machine.fields[field]); actual != expected {
+			return fmt.Errorf("Machine %s: expected %s to be %q, got %q", rs.Primary.ID, field, expected, actual)
+		}
+
+		return nil
+	}
+}
+
+func testAccCheckMachineDestroy(api *fakeAPI) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		for _, rs := range s.RootModule().Resources {
+			if rs.Type != "paperspace_machine" {
+				continue
+			}
+			if api.machineExists(rs.Primary.ID) {
+				return fmt.Errorf("Machine %s still exists", rs.Primary.ID)
+			}
+		}
+
+		return nil
+	}
+}
+
+// TestAccMachine_syntheticCassette replays a synthetic cassette, pinning down
+// the create and update request bodies and the sequence of requests.
+func TestAccMachine_syntheticCassette(t *testing.T) {
+	c := newCassette(t, "machine_basic")
+
+	config := func(name string) string {
+		return c.providerConfig() + fmt.Sprintf(`
+resource "paperspace_machine" "test" {
+  name                      = %q
+  machine_type              = "C2"
+  size                      = 50
+  billing_type              = "hourly"
+  template_id               = "tkni3aa4"
+  shutdown_timeout_in_hours = 1
+  perform_auto_snapshot     = true
+  auto_snapshot_frequency   = "week"
+  auto_snapshot_save_count  = 2
+}
+`, name)
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: c.providers(),
+		Steps: []resource.TestStep{
+			{
+				Config: config("tf-cassette-machine"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("paperspace_machine.test", "state", "ready"),
+					resource.TestCheckResourceAttr("paperspace_machine.test", "ram", "32212254720"),
+					resource.TestCheckResourceAttr("paperspace_machine.test", "cpus", "8"),
+					resource.TestCheckResourceAttr("paperspace_machine.test", "storage_total", "53687091200"),
+					resource.TestCheckResourceAttr("paperspace_machine.test", "storage_used", "0"),
+					resource.TestCheckResourceAttr("paperspace_machine.test", "auto_snapshot_frequency", "week"),
+					resource.TestCheckResourceAttr("paperspace_machine.test", "auto_snapshot_save_count", "2"),
+					resource.TestCheckResourceAttr("paperspace_machine.test", "shutdown_timeout_in_hours", "1"),
+				),
+			},
+			{
+				Config: config("tf-cassette-machine-renamed"),
+				Check:  resource.TestCheckResourceAttr("paperspace_machine.test", "name", "tf-cassette-machine-renamed"),
+			},
+		},
+	})
+}
@@ -1,20 +1,29 @@
 package provider
 
 import (
+	"context"
 	"fmt"
 	"math/rand"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/Paperspace/paperspace-go"
-	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
-	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
 // adopted from https://stackoverflow.com/questions/22892120/how-to-generate-a-random-string-of-a-fixed-length-in-go/22892986#22892986
 var chars = []rune("0123456789abcdefghijklmnopqrstuvwxyz")
-var networkCreateTimeout = "2m"
-var networkDefaultTimeout = "1m"
+var networkCreateTimeout = 2 * time.Minute
+var networkDefaultTimeout = 1 * time.Minute
 
 func randSeq(n int) string {
 	b := make([]rune, n)
@@ -30,130 +39,268 @@ func networkHandle() string {
 	return fmt.Sprint("managed_network_" + randSeq(7))
 }
 
-func updateNetworkSchema(d *schema.ResourceData, network Network, name string) {
-	d.Set("handle", network.Handle)
-	d.Set("is_taken", network.IsTaken)
-	d.Set("name", name)
-	d.Set("netmask", network.Netmask)
-	d.Set("network", network.Network)
+type networkResource struct {
+	meta interface{}
 }
 
-func resourceNetworkCreate(d *schema.ResourceData, m interface{}) error {
-	paperspaceClient := newInternalPaperspaceClient(m)
-	teamID, ok := d.Get("team_id").(int)
-	if !ok {
-		return fmt.Errorf("team_id is not an int")
+type networkResourceModel struct {
+	ID       types.String   `tfsdk:"id"`
+	TeamID   types.Int64    `tfsdk:"team_id"`
+	Handle   types.String   `tfsdk:"handle"`
+	IsTaken  types.Bool     `tfsdk:"is_taken"`
+	Netmask  types.String   `tfsdk:"netmask"`
+	Network  types.String   `tfsdk:"network"`
+	Name     types.String   `tfsdk:"name"`
+	Region   types.String   `tfsdk:"region"`
+	Timeouts timeouts.Value `tfsdk:"timeouts"`
+}
+
+func resourceNetwork() resource.Resource {
+	return &networkResource{}
+}
+
+func (r *networkResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_network"
+}
+
+func (r *networkResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	r.meta = req.ProviderData
+}
+
+func updateNetworkModel(data *networkResourceModel, network Network, name string) {
+	data.ID = types.StringValue(strconv.Itoa(network.ID))
+	data.Handle = types.StringValue(network.Handle)
+	data.IsTaken = types.BoolValue(network.IsTaken)
+	data.Name = types.StringValue(name)
+	data.Netmask = types.StringValue(network.Netmask)
+	data.Network = types.StringValue(network.Network)
+}
+
+func (r *networkResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan networkResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	timeout, diags := plan.Timeouts.Create(ctx, networkCreateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	paperspaceClient := newInternalPaperspaceClient(r.meta)
+	teamID := int(plan.TeamID.ValueInt64())
+
+	regionPath := path.Root("region")
+	regionValue := plan.Region.ValueString()
+	if regionValue == "" {
+		regionPath = path.Empty()
+		regionValue = paperspaceClient.Region
 	}
 
-	regionId, ok := RegionMap[paperspaceClient.Region]
+	region, ok := findRegion(regionValue)
 	if !ok {
-		return fmt.Errorf("Region %s not found", paperspaceClient.Region)
+		resp.Diagnostics.AddAttributeError(regionPath, "Error creating private network",
+			fmt.Sprintf("Region %q not found. Set region on the resource or the provider to one of: %s.", regionValue, regionChoices()))
+		return
+	}
+	if plan.Region.IsNull() || plan.Region.IsUnknown() {
+		plan.Region = types.StringValue(region.Name)
 	}
 
 	name := networkHandle()
 
 	createNamedNetworkParams := CreateTeamNamedNetworkParams{
 		Name:     name,
-		RegionId: regionId,
+		RegionId: region.ID,
 	}
 
-	if err := paperspaceClient.CreateTeamNamedNetwork(teamID, createNamedNetworkParams); err != nil {
-		return fmt.Errorf("Error creating private network: %s", err)
+	if err := paperspaceClient.CreateTeamNamedNetwork(ctx, teamID, createNamedNetworkParams); err != nil {
+		resp.Diagnostics.Append(attributeErrorDiagnostic(path.Root("team_id"), fmt.Sprintf("Error creating private network for team %d", teamID), err))
+		return
 	}
 
-	return resource.Retry(d.Timeout(schema.TimeoutCreate), func() *resource.RetryError {
-		paperspaceClient := newInternalPaperspaceClient(m)
-
+	err := retryContext(ctx, timeout, func(ctx context.Context) *retryError {
 		// XXX: potential race condition for multiple networks created with the name concurrently
 		// Add sync API response to API
-		namedNetwork, err := paperspaceClient.GetTeamNamedNetwork(teamID, name)
+		namedNetwork, err := paperspaceClient.GetTeamNamedNetwork(ctx, teamID, name)
 		if err != nil {
-			return resource.RetryableError(fmt.Errorf("Error creating private network: %s", err))
+			return retryableError(err)
 		}
 
-		d.SetId(strconv.Itoa(namedNetwork.Network.ID))
-		return resource.NonRetryableError(resourceNetworkRead(d, m))
+		updateNetworkModel(&plan, namedNetwork.Network, namedNetwork.Name)
+		return nil
 	})
+	if err != nil {
+		resp.Diagnostics.Append(errorDiagnostic(fmt.Sprintf("Error waiting for private network %s", name), err))
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
 }
 
-func resourceNetworkRead(d *schema.ResourceData, m interface{}) error {
-	paperspaceClient := newInternalPaperspaceClient(m)
-	teamID, ok := d.Get("team_id").(int)
-	if !ok {
-		return fmt.Errorf("team_id is not an int")
-	}
+// readNetwork refreshes data from the API, returning false if the network no
+// longer exists.
+func readNetwork(ctx context.Context, paperspaceClient PaperspaceClient, data *networkResourceModel) (bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
 
-	namedNetwork, err := paperspaceClient.GetTeamNamedNetworkById(teamID, d.Id())
+	namedNetwork, err := paperspaceClient.GetTeamNamedNetworkById(ctx, int(data.TeamID.ValueInt64()), data.ID.ValueString())
 	if err != nil {
-		d.SetId("")
-		return err
+		if IsNotFound(err) {
+			return false, diags
+		}
+
+		diags.Append(errorDiagnostic(fmt.Sprintf("Error reading private network %s", data.ID.ValueString()), err))
+		return false, diags
 	}
 
-	d.SetId(strconv.Itoa(namedNetwork.Network.ID))
-	updateNetworkSchema(d, namedNetwork.Network, namedNetwork.Name)
+	updateNetworkModel(data, namedNetwork.Network, namedNetwork.Name)
 
-	return nil
+	return true, diags
 }
 
-func resourceNetworkUpdate(d *schema.ResourceData, m interface{}) error {
-	return resourceNetworkRead(d, m)
+func (r *networkResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data networkResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	found, diags := readNetwork(ctx, newInternalPaperspaceClient(r.meta), &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if !found {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *networkResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan networkResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// region is unknown when a network from before region was an argument
+	// changes without one configured
+	if plan.Region.IsUnknown() {
+		plan.Region = types.StringNull()
+	}
+
+	_, diags := readNetwork(ctx, newInternalPaperspaceClient(r.meta), &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
 }
 
-func resourceNetworkDelete(d *schema.ResourceData, m interface{}) error {
-	paperspaceClient := newPaperspaceClient(m)
-	return resource.Retry(d.Timeout(schema.TimeoutDefault), func() *resource.RetryError {
-		if err := paperspaceClient.DeleteNetwork(d.Id(), paperspace.NetworkDeleteParams{}); err != nil {
-			if ErrNotFound(err) {
-				return resource.NonRetryableError(nil)
+func (r *networkResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data networkResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	timeout, diags := data.Timeouts.Delete(ctx, networkDefaultTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	paperspaceClient := newPaperspaceClient(r.meta)
+	err := retryContext(ctx, timeout, func(ctx context.Context) *retryError {
+		if err := paperspaceClient.DeleteNetwork(data.ID.ValueString(), paperspace.NetworkDeleteParams{
+			RequestParams: paperspace.RequestParams{Context: ctx},
+		}); err != nil {
+			if IsNotFound(err) {
+				return nonRetryableError(nil)
 			}
-			return resource.RetryableError(err)
+			if !IsRetryable(err) {
+				return nonRetryableError(err)
+			}
+			return retryableError(err)
 		}
 
-		return resource.NonRetryableError(nil)
+		return nonRetryableError(nil)
 	})
+	if err != nil {
+		resp.Diagnostics.Append(errorDiagnostic(fmt.Sprintf("Error deleting private network %s", data.ID.ValueString()), err))
+	}
 }
 
-func resourceNetwork() *schema.Resource {
-	createTimeout, _ := time.ParseDuration(networkCreateTimeout)
-	defaultTimeout, _ := time.ParseDuration(networkDefaultTimeout)
-	return &schema.Resource{
-		Create: resourceNetworkCreate,
-		Read:   resourceNetworkRead,
-		Update: resourceNetworkUpdate,
-		Delete: resourceNetworkDelete,
-		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
-		},
-		Timeouts: &schema.ResourceTimeout{
-			Create:  &createTimeout,
-			Default: &defaultTimeout,
-		},
+// ImportState takes "<team_id>/<id>", as networks can only be read through
+// the team that owns them.
+func (r *networkResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	teamIDPart, id, ok := strings.Cut(req.ID, "/")
+	teamID, err := strconv.ParseInt(teamIDPart, 10, 64)
+	if !ok || err != nil || id == "" {
+		resp.Diagnostics.AddError("Error importing paperspace network",
+			fmt.Sprintf("expected an import id of the form <team_id>/<id>, got %q", req.ID))
+		return
+	}
 
-		Schema: map[string]*schema.Schema{
-			"team_id": &schema.Schema{
-				Type:     schema.TypeInt,
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), id)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("team_id"), teamID)...)
+}
+
+func (r *networkResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	suppressEquivalentPlan(ctx, req, resp)
+}
+
+func (r *networkResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:      true,
+				PlanModifiers: []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
+			},
+			"team_id": schema.Int64Attribute{
 				Required: true,
 			},
-			"handle": &schema.Schema{
-				Type:     schema.TypeString,
+			"handle": schema.StringAttribute{
 				Computed: true,
 			},
-			"is_taken": &schema.Schema{
-				Type:     schema.TypeBool,
+			"is_taken": schema.BoolAttribute{
 				Computed: true,
 			},
-			"netmask": &schema.Schema{
-				Type:     schema.TypeString,
+			"netmask": schema.StringAttribute{
 				Computed: true,
 			},
-			"network": &schema.Schema{
-				Type:     schema.TypeString,
+			"network": schema.StringAttribute{
 				Computed: true,
 			},
-			"name": &schema.Schema{
-				Type:     schema.TypeString,
+			"name": schema.StringAttribute{
 				Computed: true,
 			},
+			// the API does not report a network's region, so it is whatever
+			// was used to create it; networks created before region was an
+			// argument have none in state and adopt the configured one
+			"region": schema.StringAttribute{
+				Optional:   true,
+				Computed:   true,
+				Validators: []validator.String{regionValidator{}},
+				PlanModifiers: []planmodifier.String{
+					regionPlanModifier{},
+					stringplanmodifier.RequiresReplaceIf(func(ctx context.Context, req planmodifier.StringRequest, resp *stringplanmodifier.RequiresReplaceIfFuncResponse) {
+						resp.RequiresReplace = !req.StateValue.IsNull() && !req.PlanValue.IsUnknown()
+					}, "Moving a network to another region requires a new network.", "Moving a network to another `region` requires a new network."),
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Delete: true,
+			}),
 		},
 	}
 }
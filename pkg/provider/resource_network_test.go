@@ -0,0 +1,174 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestAccNetwork_basic(t *testing.T) {
+	api := newFakeAPI(t)
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories(),
+		CheckDestroy:             testAccCheckNetworkDestroy(api),
+		Steps: []resource.TestStep{
+			{
+				Config: api.providerConfig() + fmt.Sprintf(`
+resource "paperspace_network" "test" {
+  team_id = %d
+}
+`, fakeTeamID),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckNetworkExists(api, "paperspace_network.test"),
+					resource.TestMatchResourceAttr("paperspace_network.test", "name", regexp.MustCompile(`^managed_network_[0-9a-z]{7}$`)),
+					resource.TestCheckResourceAttr("paperspace_network.test", "netmask", "255.255.255.0"),
+					resource.TestCheckResourceAttr("paperspace_network.test", "is_taken", "false"),
+					resource.TestCheckResourceAttrSet("paperspace_network.test", "handle"),
+					resource.TestCheckResourceAttrSet("paperspace_network.test", "network"),
+				),
+			},
+			{
+				ResourceName:      "paperspace_network.test",
+				ImportState:       true,
+				ImportStateIdFunc: testAccNetworkImportStateID("paperspace_network.test"),
+				ImportStateVerify: true,
+				// not reported by the API
+				ImportStateVerifyIgnore: []string{"region"},
+			},
+			{
+				ResourceName:  "paperspace_network.test",
+				ImportState:   true,
+				ImportStateId: "not-a-team-id",
+				ExpectError:   regexp.MustCompile(`expected an import id of the form <team_id>/<id>, got "not-a-team-id"`),
+			},
+		},
+	})
+}
+
+// TestAccNetwork_region checks that a network can be created outside the
+// provider's region, and that spelling its region differently is not a
+// change.
+func TestAccNetwork_region(t *testing.T) {
+	api := newFakeAPI(t)
+
+	config := func(region string) string {
+		return api.providerConfig() + fmt.Sprintf(`
+resource "paperspace_network" "test" {
+  team_id = %d
+  region  = %q
+}
+`, fakeTeamID, region)
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories(),
+		CheckDestroy:             testAccCheckNetworkDestroy(api),
+		Steps: []resource.TestStep{
+			{
+				Config:      config("atlantis"),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`(?s)Invalid paperspace region.*"atlantis" not found`),
+			},
+			{
+				Config: config("ams1"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckNetworkExists(api, "paperspace_network.test"),
+					testAccCheckFakeNetworkRegionID(api, "paperspace_network.test", 3),
+					resource.TestCheckResourceAttr("paperspace_network.test", "region", "ams1"),
+				),
+			},
+			{
+				Config:   config("Europe (AMS1)"),
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+// TestAccNetwork_unknownTeam checks that API errors are reported against the
+// attribute that caused them, with the API's message in the detail.
+func TestAccNetwork_unknownTeam(t *testing.T) {
+	api := newFakeAPI(t)
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: api.providerConfig() + `
+resource "paperspace_network" "test" {
+  team_id = 999
+}
+`,
+				ExpectError: regexp.MustCompile(`(?s)Error creating private network for team 999.*team_id = 999.*Team not found`),
+			},
+		},
+	})
+}
+
+func testAccCheckNetworkExists(api *fakeAPI, name string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("Not found: %s", name)
+		}
+		if !api.networkExists(rs.Primary.ID) {
+			return fmt.Errorf("Network %s does not exist", rs.Primary.ID)
+		}
+
+		return nil
+	}
+}
+
+func testAccNetworkImportStateID(name string) resource.ImportStateIdFunc {
+	return func(s *terraform.State) (string, error) {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return "", fmt.Errorf("Not found: %s", name)
+		}
+
+		return fmt.Sprintf("%s/%s", rs.Primary.Attributes["team_id"], rs.Primary.ID), nil
+	}
+}
+
+func testAccCheckFakeNetworkRegionID(api *fakeAPI, name string, regionID int) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("Not found: %s", name)
+		}
+
+		api.mu.Lock()
+		defer api.mu.Unlock()
+
+		for _, namedNetwork := range api.teamNetworks[fakeTeamID] {
+			if fmt.Sprint(namedNetwork["network"].(map[string]interface{})["id"]) != rs.Primary.ID {
+				continue
+			}
+			if actual := fmt.Sprint(namedNetwork["regionId"]); actual != fmt.Sprint(regionID) {
+				return fmt.Errorf("Network %s: expected regionId %d, got %s", rs.Primary.ID, regionID, actual)
+			}
+			return nil
+		}
+
+		return fmt.Errorf("Network %s does not exist", rs.Primary.ID)
+	}
+}
+
+func testAccCheckNetworkDestroy(api *fakeAPI) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		for _, rs := range s.RootModule().Resources {
+			if rs.Type != "paperspace_network" {
+				continue
+			}
+			if api.networkExists(rs.Primary.ID) {
+				return fmt.Errorf("Network %s still exists", rs.Primary.ID)
+			}
+		}
+
+		return nil
+	}
+}
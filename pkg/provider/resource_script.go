@@ -1,261 +1,511 @@
 package provider
 
 import (
-	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"log"
-	"net/http/httputil"
-
-	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/mapvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/resourcevalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
-func resourceScriptCreate(d *schema.ResourceData, m interface{}) error {
-	paperspaceClient := newInternalPaperspaceClient(m)
+type scriptResource struct {
+	meta interface{}
+}
 
-	log.Printf("[INFO] paperspace resourceScriptCreate Client ready")
+type scriptResourceModel struct {
+	ID          types.String `tfsdk:"id"`
+	Name        types.String `tfsdk:"name"`
+	Description types.String `tfsdk:"description"`
+	ScriptText  types.String `tfsdk:"script_text"`
+	ScriptFile  types.String `tfsdk:"script_file"`
+	ScriptVars  types.Map    `tfsdk:"script_vars"`
+	OwnerType   types.String `tfsdk:"owner_type"`
+	OwnerID     types.String `tfsdk:"owner_id"`
+	DtCreated   types.String `tfsdk:"dt_created"`
+	IsEnabled   types.Bool   `tfsdk:"is_enabled"`
+	RunOnce     types.Bool   `tfsdk:"run_once"`
+	ScriptSHA   types.String `tfsdk:"script_sha256"`
+
+	// TextHash is the hash of the API's copy of the script, normalized; it
+	// is kept in private state to detect changes to script_file.
+	TextHash string `tfsdk:"-"`
+}
 
-	region := paperspaceClient.Region
-	if r, ok := d.GetOk("region"); ok {
-		region = r.(string)
-	}
-	if region == "" {
-		return fmt.Errorf("Error creating paperspace script: missing region")
-	}
+// scriptMaxSize is the largest script, in bytes, the API accepts.
+const scriptMaxSize = 16 * 1024
 
-	body := make(MapIf)
-	body.AppendAs(d, "name", "scriptName")
-	body.AppendAs(d, "script_text", "scriptText")
-	body.AppendAsIfSet(d, "description", "scriptDescription")
-	body.AppendAsIfSet(d, "is_enabled", "isEnabled")
-	body.AppendAsIfSet(d, "run_once", "runOnce")
+// scriptTextHashKey is the private state key of scriptResourceModel.TextHash.
+const scriptTextHashKey = "script_text_hash"
 
-	data, _ := json.MarshalIndent(body, "", "  ")
-	log.Println(string(data))
+// scriptContent returns the text to upload: script_text, or script_file
+// rendered with script_vars.
+func scriptContent(ctx context.Context, data scriptResourceModel) (string, diag.Diagnostics) {
+	var diags diag.Diagnostics
 
-	url := fmt.Sprintf("%s/scripts/createScript", paperspaceClient.APIHost)
-	req, err := paperspaceClient.NewHttpRequest("POST", url, bytes.NewBuffer(data))
-	if err != nil {
-		return fmt.Errorf("Error constructing CreateScript request: %s", err)
+	if data.ScriptFile.IsNull() {
+		return data.ScriptText.ValueString(), diags
 	}
-	requestDump, err := httputil.DumpRequest(req, true)
+
+	content, err := os.ReadFile(data.ScriptFile.ValueString())
 	if err != nil {
-		return fmt.Errorf("Error constructing CreateScript request: %s", err)
+		diags.AddAttributeError(path.Root("script_file"), "Error reading paperspace script file", err.Error())
+		return "", diags
 	}
-	log.Print("[INFO] Request:", string(requestDump))
 
-	resp, err := paperspaceClient.HttpClient.Do(req)
-	if err != nil {
-		return fmt.Errorf("Error creating paperspace script: %s", err)
+	// without script_vars the file is uploaded as is, so scripts that happen
+	// to contain {{ are not mangled
+	if data.ScriptVars.IsNull() {
+		return string(content), diags
 	}
-	defer resp.Body.Close()
 
-	statusCode := resp.StatusCode
-	log.Printf("[INFO] paperspace resourceScriptCreate StatusCode: %v", statusCode)
-	if statusCode != 200 {
-		responseDump, _ := httputil.DumpResponse(resp, true)
-		return fmt.Errorf("Error reading paperspace script: Response: %s", string(responseDump))
+	vars := map[string]string{}
+	diags.Append(data.ScriptVars.ElementsAs(ctx, &vars, false)...)
+	if diags.HasError() {
+		return "", diags
 	}
 
-	var f interface{}
-	err = json.NewDecoder(resp.Body).Decode(&f)
+	tmpl, err := template.New(filepath.Base(data.ScriptFile.ValueString())).Option("missingkey=error").Parse(string(content))
 	if err != nil {
-		return fmt.Errorf("Error decoding GetScript response body: %s", err)
+		diags.AddAttributeError(path.Root("script_file"), "Error parsing paperspace script template", err.Error())
+		return "", diags
 	}
-	LogHttpResponse("paperspace dataSourceGetScript", req.URL, resp, f, err)
 
-	mp := f.(map[string]interface{})
-	id, _ := mp["id"].(string)
-
-	if id == "" {
-		return fmt.Errorf("Error in paperspace script create data: id not found")
+	var rendered strings.Builder
+	if err := tmpl.Execute(&rendered, vars); err != nil {
+		diags.AddAttributeError(path.Root("script_vars"), "Error rendering paperspace script template", err.Error())
+		return "", diags
 	}
 
-	log.Printf("[INFO] paperspace resourceScriptCreate returned id: %v", id)
+	return rendered.String(), diags
+}
 
-	SetResData(d, mp, "name")
-	SetResData(d, mp, "description")
-	SetResDataFrom(d, mp, "owner_type", "ownerType")
-	SetResDataFrom(d, mp, "owner_id", "ownerId")
-	SetResDataFrom(d, mp, "dt_created", "dtCreated")
-	SetResDataFrom(d, mp, "is_enabled", "isEnabled")
-	SetResDataFrom(d, mp, "run_once", "runOnce")
+// privateState is the private state of a resource response.
+type privateState interface {
+	SetKey(ctx context.Context, key string, value []byte) diag.Diagnostics
+}
 
-	d.SetId(id)
+// setScriptTextHash stores data.TextHash in private state.
+func setScriptTextHash(ctx context.Context, private privateState, data scriptResourceModel) diag.Diagnostics {
+	value, _ := json.Marshal(data.TextHash)
+	return private.SetKey(ctx, scriptTextHashKey, value)
+}
 
-	return resourceScriptRead(d, m)
+func resourceScript() resource.Resource {
+	return &scriptResource{}
 }
 
-func resourceScriptRead(d *schema.ResourceData, m interface{}) error {
-	paperspaceClient := newInternalPaperspaceClient(m)
+func (r *scriptResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_script"
+}
 
-	log.Printf("[INFO] paperspace resourceScriptRead Client ready")
+func (r *scriptResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	r.meta = req.ProviderData
+}
 
-	url := fmt.Sprintf("%s/scripts/getScript?scriptId=%s", paperspaceClient.APIHost, d.Id())
-	req, err := paperspaceClient.NewHttpRequest("GET", url, nil)
-	if err != nil {
-		return fmt.Errorf("Error constructing GetScript request: %s", err)
+func updateScriptModel(data *scriptResourceModel, script Script) {
+	data.Name = types.StringValue(script.Name)
+	data.Description = optionalStringValue(script.Description, data.Description)
+	data.OwnerType = types.StringValue(script.OwnerType)
+	data.OwnerID = types.StringValue(script.OwnerID)
+	data.DtCreated = types.StringValue(script.DtCreated)
+	data.IsEnabled = types.BoolValue(script.IsEnabled)
+	data.RunOnce = types.BoolValue(script.RunOnce)
+}
+
+func (r *scriptResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan scriptResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
-	resp, err := paperspaceClient.HttpClient.Do(req)
-	if err != nil {
-		return fmt.Errorf("Error completing GetScript request: %s", err)
+	paperspaceClient := newInternalPaperspaceClient(r.meta)
+
+	log.Printf("[INFO] paperspace resourceScriptCreate Client ready")
+
+	if paperspaceClient.Region == "" {
+		resp.Diagnostics.AddError("Missing paperspace script region",
+			"Scripts are created in the provider's region. Set region in the provider configuration or export PAPERSPACE_REGION.")
+		return
 	}
-	defer resp.Body.Close()
 
-	statusCode := resp.StatusCode
-	log.Printf("[INFO] paperspace resourceScriptRead StatusCode: %v", statusCode)
-	if statusCode == 404 {
-		log.Printf("[INFO] paperspace resourceScriptRead scriptId not found; removing resource %s", d.Id())
-		d.SetId("")
-		return nil
+	text, diags := scriptContent(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
-	var body interface{}
-	json.NewDecoder(resp.Body).Decode(&body)
-	LogHttpResponse("paperspace resourceScriptCreate", req.URL, resp, body, err)
 
-	if statusCode != 200 {
-		return fmt.Errorf("Error reading paperspace script: Response: %s", body)
+	params := ScriptCreateParams{
+		Name:        plan.Name.ValueString(),
+		Text:        text,
+		Description: plan.Description.ValueString(),
+	}
+	if !plan.IsEnabled.IsNull() && !plan.IsEnabled.IsUnknown() {
+		isEnabled := plan.IsEnabled.ValueBool()
+		params.IsEnabled = &isEnabled
+	}
+	if !plan.RunOnce.IsNull() && !plan.RunOnce.IsUnknown() {
+		runOnce := plan.RunOnce.ValueBool()
+		params.RunOnce = &runOnce
 	}
 
+	script, err := paperspaceClient.CreateScript(ctx, params)
 	if err != nil {
-		return fmt.Errorf("Error unmarshalling paperspace script read response: %s", err)
+		resp.Diagnostics.Append(errorDiagnostic("Error creating paperspace script", err))
+		return
 	}
+	id := script.ID
 
-	mp := body.(map[string]interface{})
-	id, _ := mp["id"].(string)
+	log.Printf("[INFO] paperspace resourceScriptCreate returned id: %v", id)
+
+	plan.ID = types.StringValue(id)
+	updateScriptModel(&plan, script)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), plan.ID)...)
 
-	if id == "" {
-		log.Printf("[WARNING] paperspace resourceScriptRead script id not found; removing resource %s", d.Id())
-		d.SetId("")
-		return nil
+	found, diags := readScript(ctx, paperspaceClient, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if !found {
+		resp.Diagnostics.AddError("Error reading paperspace script", fmt.Sprintf("script %s not found after create", id))
+		return
 	}
 
-	log.Printf("[INFO] paperspace resourceScriptRead returned id: %v", id)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+	resp.Diagnostics.Append(setScriptTextHash(ctx, resp.Private, plan)...)
+}
 
-	SetResData(d, mp, "name")
-	SetResData(d, mp, "description")
-	SetResDataFrom(d, mp, "owner_type", "ownerType")
-	SetResDataFrom(d, mp, "owner_id", "ownerId")
-	SetResDataFrom(d, mp, "dt_created", "dtCreated")
-	SetResDataFrom(d, mp, "is_enabled", "isEnabled")
-	SetResDataFrom(d, mp, "run_once", "runOnce")
+// readScript refreshes data from the API, returning false if the script no
+// longer exists. script_text keeps its prior value when the API's copy only
+// differs in line endings, and is left unset for scripts from script_file.
+func readScript(ctx context.Context, paperspaceClient PaperspaceClient, data *scriptResourceModel) (bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	id := data.ID.ValueString()
 
-	url = fmt.Sprintf("%s/scripts/getScriptText?scriptId=%s", paperspaceClient.APIHost, d.Id())
-	req, err = paperspaceClient.NewHttpRequest("GET", url, nil)
+	script, err := paperspaceClient.GetScript(ctx, id)
 	if err != nil {
-		return fmt.Errorf("Error constructing GetScriptText request: %s", err)
+		if IsNotFound(err) {
+			log.Printf("[INFO] paperspace resourceScriptRead script not found; removing resource %s", id)
+			return false, diags
+		}
+
+		diags.Append(errorDiagnostic(fmt.Sprintf("Error reading paperspace script %s", id), err))
+		return false, diags
 	}
 
-	resp, err = paperspaceClient.HttpClient.Do(req)
+	updateScriptModel(data, script)
+
+	scriptText, err := paperspaceClient.GetScriptText(ctx, id)
 	if err != nil {
-		return fmt.Errorf("Error reading paperspace script text: %s", err)
+		if IsNotFound(err) {
+			log.Printf("[INFO] paperspace resourceScriptRead text of script %s not found", id)
+			if data.ScriptSHA.IsUnknown() {
+				data.ScriptSHA = types.StringNull()
+			}
+			return true, diags
+		}
+
+		diags.Append(errorDiagnostic(fmt.Sprintf("Error reading paperspace script %s text", id), err))
+		return false, diags
+	}
+
+	if data.ScriptFile.IsNull() {
+		data.ScriptText = scriptTextStateValue(scriptText, data.ScriptText)
 	}
-	defer resp.Body.Close()
+	data.ScriptSHA = types.StringValue(scriptSHA256(scriptText))
+	data.TextHash = scriptSHA256(normalizeScriptText(scriptText))
+
+	return true, diags
+}
+
+// normalizeScriptText drops carriage returns before line feeds and trailing
+// newlines, which editors and the API add or strip at will.
+func normalizeScriptText(s string) string {
+	return strings.TrimRight(strings.ReplaceAll(s, "\r\n", "\n"), "\n")
+}
+
+// scriptTextStateValue returns the text the API holds, keeping prior when it
+// only differs in line endings.
+func scriptTextStateValue(v string, prior types.String) types.String {
+	if !prior.IsNull() && !prior.IsUnknown() && normalizeScriptText(v) == normalizeScriptText(prior.ValueString()) {
+		return prior
+	}
+
+	return types.StringValue(v)
+}
+
+// scriptSHA256 hashes the text exactly as the API holds it.
+func scriptSHA256(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+// scriptTextPlanModifier keeps script_text in state when the configuration
+// only differs from it in line endings.
+type scriptTextPlanModifier struct{}
 
-	statusCode = resp.StatusCode
-	log.Printf("[INFO] paperspace resourceScriptRead text StatusCode: %v", statusCode)
+func (m scriptTextPlanModifier) Description(ctx context.Context) string {
+	return "CRLF line endings and trailing newlines do not cause a diff."
+}
 
-	json.NewDecoder(resp.Body).Decode(&body)
-	s, err := json.Marshal(body)
-	LogHttpResponse("paperspace resourceScriptCreate", req.URL, resp, body, err)
+func (m scriptTextPlanModifier) MarkdownDescription(ctx context.Context) string {
+	return m.Description(ctx)
+}
 
-	if statusCode == 404 {
-		log.Printf("[INFO] paperspace resourceScriptRead text scriptId not found")
-		return nil
+func (m scriptTextPlanModifier) PlanModifyString(ctx context.Context, req planmodifier.StringRequest, resp *planmodifier.StringResponse) {
+	if req.StateValue.IsNull() || req.PlanValue.IsNull() || req.PlanValue.IsUnknown() {
+		return
 	}
-	if statusCode != 200 {
-		return fmt.Errorf("Error reading paperspace script text: Response: %s", body)
+
+	if normalizeScriptText(req.PlanValue.ValueString()) == normalizeScriptText(req.StateValue.ValueString()) {
+		resp.PlanValue = req.StateValue
 	}
+}
 
-	d.Set("script_text", s)
+func (r *scriptResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data scriptResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-	return nil
+	found, diags := readScript(ctx, newInternalPaperspaceClient(r.meta), &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if !found {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	resp.Diagnostics.Append(setScriptTextHash(ctx, resp.Private, data)...)
 }
 
-func resourceScriptUpdate(d *schema.ResourceData, m interface{}) error {
+// Update only refreshes state: the API cannot update scripts, so every
+// argument forces a new one.
+func (r *scriptResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan scriptResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-	log.Printf("[INFO] paperspace resourceScriptUpdate Client ready")
+	id := plan.ID.ValueString()
+	found, diags := readScript(ctx, newInternalPaperspaceClient(r.meta), &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if !found {
+		resp.Diagnostics.AddError("Error reading paperspace script", fmt.Sprintf("script %s not found after update", id))
+		return
+	}
 
-	return resourceScriptRead(d, m)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+	resp.Diagnostics.Append(setScriptTextHash(ctx, resp.Private, plan)...)
 }
 
-func resourceScriptDelete(d *schema.ResourceData, m interface{}) error {
-	paperspaceClient := newInternalPaperspaceClient(m)
+func (r *scriptResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data scriptResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	paperspaceClient := newInternalPaperspaceClient(r.meta)
 
 	log.Printf("[INFO] paperspace resourceScriptDelete Client ready")
 
-	url := fmt.Sprintf("%s/scripts/%s/destroy", paperspaceClient.APIHost, d.Id())
-	req, err := paperspaceClient.NewHttpRequest("POST", url, nil)
-	if err != nil {
-		return fmt.Errorf("Error constructing DeleteScript request: %s", err)
+	id := data.ID.ValueString()
+	if err := paperspaceClient.DeleteScript(ctx, id); err != nil {
+		if IsNotFound(err) {
+			log.Printf("[INFO] paperspace resourceScriptDelete script %s already deleted", id)
+			return
+		}
+
+		resp.Diagnostics.Append(errorDiagnostic(fmt.Sprintf("Error deleting paperspace script %s", id), err))
+		return
 	}
-	resp, err := paperspaceClient.HttpClient.Do(req)
-	if err != nil {
-		return fmt.Errorf("Error deleting paperspace script: %s", err)
+
+	log.Printf("[INFO] paperspace resourceScriptDelete script %s deleted", id)
+}
+
+func (r *scriptResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+// ModifyPlan checks the size of the script, keeps script_sha256 unless the
+// script changes, drops updates that only change line endings, and replaces
+// the script when the content of script_file changes.
+func (r *scriptResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	// first, so that it cannot undo the script_sha256 planned below
+	suppressEquivalentPlan(ctx, req, resp)
+
+	var plan scriptResourceModel
+	resp.Diagnostics.Append(resp.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() || plan.ScriptText.IsUnknown() || plan.ScriptFile.IsUnknown() || plan.ScriptVars.IsUnknown() {
+		return
+	}
+
+	text, diags := scriptContent(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
-	defer resp.Body.Close()
 
-	statusCode := resp.StatusCode
-	log.Printf("[INFO] paperspace resourceScriptDelete StatusCode: %v", statusCode)
-	LogHttpResponse("paperspace resourceScriptDelete", req.URL, resp, nil, err)
-	if statusCode != 204 && statusCode != 404 {
-		return fmt.Errorf("Error deleting paperspace script: Response: %s", resp.Body)
+	if len(text) > scriptMaxSize {
+		attribute := "script_text"
+		if !plan.ScriptFile.IsNull() {
+			attribute = "script_file"
+		}
+		resp.Diagnostics.AddAttributeError(path.Root(attribute), "Paperspace script too large",
+			fmt.Sprintf("The script is %d bytes; the API accepts at most %d.", len(text), scriptMaxSize))
+		return
 	}
-	if statusCode == 204 {
-		log.Printf("[INFO] paperspace resourceScriptDelete script deleted successfully, StatusCode: %v", statusCode)
+
+	if req.State.Raw.IsNull() {
+		return
 	}
-	if statusCode == 404 {
-		log.Printf("[INFO] paperspace resourceScriptDelete script already deleted, StatusCode: %v", statusCode)
+
+	var state scriptResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
-	return nil
+	unchanged := plan.ScriptFile.IsNull() && plan.ScriptText.Equal(state.ScriptText)
+	if !plan.ScriptFile.IsNull() {
+		textHash, diags := req.Private.GetKey(ctx, scriptTextHashKey)
+		resp.Diagnostics.Append(diags...)
+
+		var remoteHash string
+		json.Unmarshal(textHash, &remoteHash)
+		unchanged = remoteHash != "" && remoteHash == scriptSHA256(normalizeScriptText(text))
+	}
+	if unchanged {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("script_sha256"), state.ScriptSHA)...)
+		return
+	}
+
+	// script_sha256 is the only change planned when just the content of
+	// script_file changed, so it is what forces the new script
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("script_sha256"), types.StringUnknown())...)
+	resp.RequiresReplace = append(resp.RequiresReplace, path.Root("script_sha256"))
+}
+
+func (r *scriptResource) ConfigValidators(ctx context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		resourcevalidator.ExactlyOneOf(path.MatchRoot("script_text"), path.MatchRoot("script_file")),
+	}
 }
 
-func resourceScript() *schema.Resource {
-	return &schema.Resource{
-		Create: resourceScriptCreate,
-		Read:   resourceScriptRead,
-		Update: resourceScriptUpdate,
-		Delete: resourceScriptDelete,
-		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+// UpgradeState migrates state written by the SDK based releases, which
+// stored an unset description as "".
+func (r *scriptResource) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		0: {
+			StateUpgrader: func(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+				upgradeStateJSON(ctx, req, resp, func(attributes map[string]interface{}) error {
+					zeroToNull(attributes, "description")
+					return nil
+				})
+			},
 		},
+	}
+}
 
-		Schema: map[string]*schema.Schema{
-			"name": &schema.Schema{
-				Type:     schema.TypeString,
-				Required: true,
+func (r *scriptResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Version: 1,
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:      true,
+				PlanModifiers: []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
+			},
+			// the API cannot update scripts, so every argument forces a new one
+			"name": schema.StringAttribute{
+				Required:      true,
+				PlanModifiers: []planmodifier.String{stringplanmodifier.RequiresReplace()},
 			},
-			"description": &schema.Schema{
-				Type:     schema.TypeString,
+			"description": schema.StringAttribute{
+				Optional:      true,
+				PlanModifiers: []planmodifier.String{stringplanmodifier.RequiresReplace()},
+			},
+			"script_text": schema.StringAttribute{
 				Optional: true,
+				PlanModifiers: []planmodifier.String{
+					scriptTextPlanModifier{},
+					stringplanmodifier.RequiresReplace(),
+				},
 			},
-			"script_text": &schema.Schema{
-				Type:     schema.TypeString,
-				Required: true,
+			"script_file": schema.StringAttribute{
+				Optional:      true,
+				Validators:    []validator.String{stringvalidator.LengthAtLeast(1)},
+				PlanModifiers: []planmodifier.String{stringplanmodifier.RequiresReplace()},
 			},
-			"owner_type": &schema.Schema{
-				Type:     schema.TypeString,
-				Computed: true,
+			"script_vars": schema.MapAttribute{
+				Optional:      true,
+				ElementType:   types.StringType,
+				Validators:    []validator.Map{mapvalidator.AlsoRequires(path.MatchRoot("script_file"))},
+				PlanModifiers: []planmodifier.Map{mapplanmodifier.RequiresReplace()},
 			},
-			"owner_id": &schema.Schema{
-				Type:     schema.TypeString,
+			"script_sha256": schema.StringAttribute{
 				Computed: true,
 			},
-			"dt_created": &schema.Schema{
-				Type:     schema.TypeString,
-				Computed: true,
+			"owner_type": schema.StringAttribute{
+				Computed:      true,
+				PlanModifiers: []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
+			},
+			"owner_id": schema.StringAttribute{
+				Computed:      true,
+				PlanModifiers: []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
+			},
+			"dt_created": schema.StringAttribute{
+				Computed:      true,
+				PlanModifiers: []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
 			},
-			"is_enabled": &schema.Schema{
-				Type:     schema.TypeBool,
+			// unset, these keep whatever the API has
+			"is_enabled": schema.BoolAttribute{
 				Optional: true,
 				Computed: true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+					boolplanmodifier.RequiresReplace(),
+				},
 			},
-			"run_once": &schema.Schema{
-				Type:     schema.TypeBool,
+			"run_once": schema.BoolAttribute{
 				Optional: true,
 				Computed: true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+					boolplanmodifier.RequiresReplace(),
+				},
 			},
 		},
 	}
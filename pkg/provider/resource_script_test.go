@@ -0,0 +1,355 @@
+package provider
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestAccScript_basic(t *testing.T) {
+	api := newFakeAPI(t)
+	config := api.providerConfig() + `
+resource "paperspace_script" "test" {
+  name        = "tf-acc-script"
+  description = "installs things"
+  script_text = "#!/bin/bash\necho hello"
+  run_once    = true
+}
+`
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories(),
+		CheckDestroy:             testAccCheckScriptDestroy(api),
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckScriptExists(api, "paperspace_script.test"),
+					resource.TestCheckResourceAttr("paperspace_script.test", "name", "tf-acc-script"),
+					resource.TestCheckResourceAttr("paperspace_script.test", "description", "installs things"),
+					resource.TestCheckResourceAttr("paperspace_script.test", "owner_type", "team"),
+					resource.TestCheckResourceAttr("paperspace_script.test", "is_enabled", "true"),
+					resource.TestCheckResourceAttr("paperspace_script.test", "run_once", "true"),
+					resource.TestCheckResourceAttr("paperspace_script.test", "script_text", "#!/bin/bash\necho hello"),
+					resource.TestCheckResourceAttr("paperspace_script.test", "script_sha256", scriptSHA256("#!/bin/bash\necho hello")),
+				),
+			},
+			{
+				ResourceName:      "paperspace_script.test",
+				Config:            config,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+// TestAccScript_update checks that changes replace the script, since the API
+// cannot update scripts, rather than only being recorded in state.
+func TestAccScript_update(t *testing.T) {
+	api := newFakeAPI(t)
+
+	var id string
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories(),
+		CheckDestroy:             testAccCheckScriptDestroy(api),
+		Steps: []resource.TestStep{
+			{
+				Config: api.providerConfig() + `
+resource "paperspace_script" "test" {
+  name        = "tf-acc-script"
+  description = "installs things"
+  script_text = "#!/bin/bash\necho hello"
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckFakeScriptText(api, "paperspace_script.test", "#!/bin/bash\necho hello"),
+					func(s *terraform.State) error {
+						id = s.RootModule().Resources["paperspace_script.test"].Primary.ID
+						return nil
+					},
+				),
+			},
+			{
+				Config: api.providerConfig() + `
+resource "paperspace_script" "test" {
+  name        = "tf-acc-script-renamed"
+  script_text = "#!/bin/bash\necho goodbye"
+  is_enabled  = false
+  run_once    = true
+}
+`,
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("paperspace_script.test", plancheck.ResourceActionDestroyBeforeCreate),
+					},
+				},
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrWith("paperspace_script.test", "id", func(value string) error {
+						if value == id {
+							return fmt.Errorf("script %s was not replaced", id)
+						}
+						if api.scriptExists(id) {
+							return fmt.Errorf("script %s still exists", id)
+						}
+						return nil
+					}),
+					testAccCheckFakeScriptText(api, "paperspace_script.test", "#!/bin/bash\necho goodbye"),
+					resource.TestCheckResourceAttr("paperspace_script.test", "name", "tf-acc-script-renamed"),
+					resource.TestCheckNoResourceAttr("paperspace_script.test", "description"),
+					resource.TestCheckResourceAttr("paperspace_script.test", "is_enabled", "false"),
+					resource.TestCheckResourceAttr("paperspace_script.test", "run_once", "true"),
+				),
+			},
+		},
+	})
+}
+
+// TestAccScript_drift checks that script_text is read back verbatim: edits
+// made outside Terraform show up as a diff, line ending changes do not.
+func TestAccScript_drift(t *testing.T) {
+	api := newFakeAPI(t)
+	config := api.providerConfig() + `
+resource "paperspace_script" "test" {
+  name        = "tf-acc-script"
+  script_text = "#!/bin/bash\necho hello\n"
+}
+`
+
+	setText := func(text string) func() {
+		return func() {
+			api.mu.Lock()
+			defer api.mu.Unlock()
+
+			for _, script := range api.scripts {
+				script.text = text
+			}
+		}
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories(),
+		CheckDestroy:             testAccCheckScriptDestroy(api),
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check:  resource.TestCheckResourceAttr("paperspace_script.test", "script_sha256", scriptSHA256("#!/bin/bash\necho hello\n")),
+			},
+			{
+				PreConfig: setText("#!/bin/bash\r\necho hello"),
+				Config:    config,
+				PlanOnly:  true,
+			},
+			{
+				PreConfig:          setText("#!/bin/bash\necho tampered\n"),
+				Config:             config,
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckFakeScriptText(api, "paperspace_script.test", "#!/bin/bash\necho hello\n"),
+					resource.TestCheckResourceAttr("paperspace_script.test", "script_sha256", scriptSHA256("#!/bin/bash\necho hello\n")),
+				),
+			},
+			{
+				Config: api.providerConfig() + `
+resource "paperspace_script" "test" {
+  name        = "tf-acc-script"
+  script_text = "#!/bin/bash\r\necho hello\r\n"
+}
+`,
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+// TestAccScript_file checks that scripts can come from templated files, are
+// kept out of state, and are replaced when the rendered script changes.
+func TestAccScript_file(t *testing.T) {
+	api := newFakeAPI(t)
+	scriptFile := filepath.Join(t.TempDir(), "startup.sh")
+
+	config := func(greeting string) string {
+		return api.providerConfig() + fmt.Sprintf(`
+resource "paperspace_script" "test" {
+  name        = "tf-acc-script"
+  script_file = %q
+  script_vars = {
+    greeting = %q
+  }
+}
+`, scriptFile, greeting)
+	}
+	writeScript := func(content string) func() {
+		return func() {
+			if err := os.WriteFile(scriptFile, []byte(content), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories(),
+		CheckDestroy:             testAccCheckScriptDestroy(api),
+		Steps: []resource.TestStep{
+			{
+				PreConfig: writeScript("#!/bin/bash\necho {{ .greeting }} ${HOME}\n"),
+				Config:    config("hello"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckFakeScriptText(api, "paperspace_script.test", "#!/bin/bash\necho hello ${HOME}\n"),
+					resource.TestCheckNoResourceAttr("paperspace_script.test", "script_text"),
+					resource.TestCheckResourceAttr("paperspace_script.test", "script_sha256", scriptSHA256("#!/bin/bash\necho hello ${HOME}\n")),
+				),
+			},
+			{
+				Config: config("goodbye"),
+				Check:  testAccCheckFakeScriptText(api, "paperspace_script.test", "#!/bin/bash\necho goodbye ${HOME}\n"),
+			},
+			{
+				PreConfig: writeScript("#!/bin/bash\necho {{ .greeting }} again\n"),
+				Config:    config("goodbye"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("paperspace_script.test", plancheck.ResourceActionDestroyBeforeCreate),
+					},
+				},
+				Check: testAccCheckFakeScriptText(api, "paperspace_script.test", "#!/bin/bash\necho goodbye again\n"),
+			},
+			{
+				PreConfig: writeScript("#!/bin/bash\r\necho {{ .greeting }} again"),
+				Config:    config("goodbye"),
+				PlanOnly:  true,
+			},
+		},
+	})
+}
+
+func TestAccScript_invalid(t *testing.T) {
+	api := newFakeAPI(t)
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: api.providerConfig() + `
+resource "paperspace_script" "test" {
+  name = "tf-acc-script"
+}
+`,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`(?s)Exactly one of these attributes must be configured: \[script_text,script_file\]`),
+			},
+			{
+				Config: api.providerConfig() + `
+resource "paperspace_script" "test" {
+  name        = "tf-acc-script"
+  script_text = "echo hello"
+  script_vars = {
+    greeting = "hello"
+  }
+}
+`,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`(?s)Invalid Attribute Combination`),
+			},
+			{
+				Config: api.providerConfig() + fmt.Sprintf(`
+resource "paperspace_script" "test" {
+  name        = "tf-acc-script"
+  script_text = %q
+}
+`, strings.Repeat("#", scriptMaxSize+1)),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`(?s)Paperspace script too large.*16385 bytes`),
+			},
+		},
+	})
+}
+
+func testAccCheckFakeScriptText(api *fakeAPI, name, expected string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("Not found: %s", name)
+		}
+
+		api.mu.Lock()
+		defer api.mu.Unlock()
+
+		script, ok := api.scripts[rs.Primary.ID]
+		if !ok {
+			return fmt.Errorf("Script %s does not exist", rs.Primary.ID)
+		}
+		if script.text != expected {
+			return fmt.Errorf("Script %s: expected text %q, got %q", rs.Primary.ID, expected, script.text)
+		}
+
+		return nil
+	}
+}
+
+func testAccCheckScriptExists(api *fakeAPI, name string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("Not found: %s", name)
+		}
+		if !api.scriptExists(rs.Primary.ID) {
+			return fmt.Errorf("Script %s does not exist", rs.Primary.ID)
+		}
+
+		return nil
+	}
+}
+
+func testAccCheckScriptDestroy(api *fakeAPI) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		for _, rs := range s.RootModule().Resources {
+			if rs.Type != "paperspace_script" {
+				continue
+			}
+			if api.scriptExists(rs.Primary.ID) {
+				return fmt.Errorf("Script %s still exists", rs.Primary.ID)
+			}
+		}
+
+		return nil
+	}
+}
+
+// TestAccScript_syntheticCassette replays a synthetic cassette, pinning down
+// the createScript body built from the optional attributes.
+func TestAccScript_syntheticCassette(t *testing.T) {
+	c := newCassette(t, "script_basic")
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: c.providers(),
+		Steps: []resource.TestStep{
+			{
+				Config: c.providerConfig() + `
+resource "paperspace_script" "test" {
+  name        = "tf-cassette-script"
+  description = "installs things"
+  script_text = "#!/bin/bash\necho hello"
+  is_enabled  = true
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("paperspace_script.test", "owner_type", "team"),
+					resource.TestCheckResourceAttr("paperspace_script.test", "is_enabled", "true"),
+					resource.TestCheckResourceAttr("paperspace_script.test", "run_once", "false"),
+				),
+			},
+		},
+	})
+}
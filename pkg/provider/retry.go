@@ -0,0 +1,194 @@
+package provider
+
+import (
+	"context"
+	"io"
+	"log"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultMaxRetries     = 3
+	defaultRetryMaxWait   = 30 * time.Second
+	defaultRetryMinWait   = 1 * time.Second
+	defaultAttemptTimeout = 30 * time.Second
+)
+
+// retryTransport retries requests that failed for transient reasons with
+// exponential backoff and jitter, honoring Retry-After when the API sends it.
+// Each attempt gets its own timeout so that backoff waits do not eat into
+// the time allowed for the request itself.
+type retryTransport struct {
+	next           http.RoundTripper
+	maxRetries     int
+	minWait        time.Duration
+	maxWait        time.Duration
+	attemptTimeout time.Duration
+}
+
+func newRetryTransport(next http.RoundTripper, maxRetries int, maxWait time.Duration, attemptTimeout time.Duration) *retryTransport {
+	if maxWait <= 0 {
+		maxWait = defaultRetryMaxWait
+	}
+
+	return &retryTransport{
+		next:           next,
+		maxRetries:     maxRetries,
+		minWait:        defaultRetryMinWait,
+		maxWait:        maxWait,
+		attemptTimeout: attemptTimeout,
+	}
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		attemptReq, cancel, err := t.attemptRequest(req)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := t.next.RoundTrip(attemptReq)
+		if err != nil {
+			cancel()
+		} else {
+			resp.Body = &cancelOnCloseBody{ReadCloser: resp.Body, cancel: cancel}
+		}
+
+		if attempt >= t.maxRetries || !shouldRetryRequest(req, resp, err) {
+			return resp, err
+		}
+
+		wait := t.backoff(attempt, resp)
+		if resp != nil {
+			log.Printf("[DEBUG] paperspace request %s %s returned %s, retrying in %s (attempt %d of %d)", req.Method, req.URL, resp.Status, wait, attempt+1, t.maxRetries)
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		} else {
+			log.Printf("[DEBUG] paperspace request %s %s failed: %s, retrying in %s (attempt %d of %d)", req.Method, req.URL, err, wait, attempt+1, t.maxRetries)
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// attemptRequest clones req with a fresh body and a per-attempt timeout.
+func (t *retryTransport) attemptRequest(req *http.Request) (*http.Request, context.CancelFunc, error) {
+	ctx, cancel := req.Context(), context.CancelFunc(func() {})
+	if t.attemptTimeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, t.attemptTimeout)
+	}
+
+	attemptReq := req.Clone(ctx)
+	if req.Body != nil && req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			cancel()
+			return nil, nil, err
+		}
+		attemptReq.Body = body
+	}
+
+	return attemptReq, cancel, nil
+}
+
+func (t *retryTransport) backoff(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if wait, ok := retryAfter(resp); ok {
+			if wait > t.maxWait {
+				return t.maxWait
+			}
+			return wait
+		}
+	}
+
+	wait := time.Duration(float64(t.minWait) * math.Pow(2, float64(attempt)))
+	if wait > t.maxWait || wait <= 0 {
+		wait = t.maxWait
+	}
+
+	// full jitter over the upper half of the window keeps parallel applies
+	// from retrying in lockstep
+	half := int64(wait / 2)
+	return time.Duration(half + rand.Int63n(half+1))
+}
+
+// retryAfter parses the Retry-After header, given either in seconds or as an
+// HTTP date.
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		wait := time.Until(date)
+		if wait < 0 {
+			wait = 0
+		}
+		return wait, true
+	}
+
+	return 0, false
+}
+
+// shouldRetryRequest only retries when doing so cannot apply a change twice:
+// rate limited requests were rejected before being processed, while gateway
+// errors and connection failures are only retried for idempotent methods.
+func shouldRetryRequest(req *http.Request, resp *http.Response, err error) bool {
+	if req.Context().Err() != nil {
+		return false
+	}
+	if req.Body != nil && req.GetBody == nil {
+		return false
+	}
+
+	if err != nil {
+		return isIdempotent(req.Method)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests:
+		return true
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return isIdempotent(req.Method)
+	}
+
+	return false
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+
+	return false
+}
+
+// cancelOnCloseBody releases the per-attempt context once the caller is done
+// reading the response.
+type cancelOnCloseBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnCloseBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+
+	return err
+}
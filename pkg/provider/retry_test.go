@@ -0,0 +1,129 @@
+package provider
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRetryAfter(t *testing.T) {
+	cases := []struct {
+		name    string
+		value   string
+		ok      bool
+		minWait time.Duration
+		maxWait time.Duration
+	}{
+		{name: "missing", value: "", ok: false},
+		{name: "seconds", value: "7", ok: true, minWait: 7 * time.Second, maxWait: 7 * time.Second},
+		{name: "zero seconds", value: "0", ok: true},
+		{name: "negative seconds", value: "-1", ok: false},
+		{name: "http date", value: time.Now().Add(20 * time.Second).UTC().Format(http.TimeFormat), ok: true, minWait: 18 * time.Second, maxWait: 20 * time.Second},
+		{name: "past http date", value: time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat), ok: true},
+		{name: "garbage", value: "soon", ok: false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp := &http.Response{Header: http.Header{}}
+			if tc.value != "" {
+				resp.Header.Set("Retry-After", tc.value)
+			}
+
+			wait, ok := retryAfter(resp)
+			if ok != tc.ok {
+				t.Fatalf("expected ok %v, got %v", tc.ok, ok)
+			}
+			if wait < tc.minWait || wait > tc.maxWait {
+				t.Errorf("expected a wait between %s and %s, got %s", tc.minWait, tc.maxWait, wait)
+			}
+		})
+	}
+}
+
+func TestRetryTransport_backoff(t *testing.T) {
+	transport := newRetryTransport(http.DefaultTransport, 10, 4*time.Second, 0)
+
+	for attempt := 0; attempt < 10; attempt++ {
+		if wait := transport.backoff(attempt, nil); wait <= 0 || wait > 4*time.Second {
+			t.Errorf("attempt %d: expected a wait up to 4s, got %s", attempt, wait)
+		}
+	}
+
+	resp := &http.Response{Header: http.Header{"Retry-After": {"120"}}}
+	if wait := transport.backoff(0, resp); wait != 4*time.Second {
+		t.Errorf("expected Retry-After to be capped at 4s, got %s", wait)
+	}
+}
+
+func TestRetryTransport(t *testing.T) {
+	cases := []struct {
+		name     string
+		method   string
+		status   int
+		attempts int
+	}{
+		{name: "GET on 503 is retried", method: "GET", status: http.StatusServiceUnavailable, attempts: 3},
+		{name: "POST on 503 is not retried", method: "POST", status: http.StatusServiceUnavailable, attempts: 1},
+		{name: "POST on 429 is retried", method: "POST", status: http.StatusTooManyRequests, attempts: 3},
+		{name: "GET on 400 is not retried", method: "GET", status: http.StatusBadRequest, attempts: 1},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var mu sync.Mutex
+			var bodies []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+
+				mu.Lock()
+				bodies = append(bodies, string(body))
+				attempt := len(bodies)
+				mu.Unlock()
+
+				if attempt < 3 {
+					w.Header().Set("Retry-After", "0")
+					w.WriteHeader(tc.status)
+					return
+				}
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			transport := newRetryTransport(http.DefaultTransport, 3, time.Millisecond, time.Second)
+			transport.minWait = time.Millisecond
+			client := &http.Client{Transport: transport}
+
+			var body io.Reader
+			if tc.method == "POST" {
+				body = strings.NewReader(`{"machineName":"test"}`)
+			}
+			req, err := http.NewRequest(tc.method, server.URL, body)
+			if err != nil {
+				t.Fatalf("err: %s", err)
+			}
+
+			resp, err := client.Do(req)
+			if err != nil {
+				t.Fatalf("err: %s", err)
+			}
+			resp.Body.Close()
+
+			if len(bodies) != tc.attempts {
+				t.Fatalf("expected %d attempts, got %d", tc.attempts, len(bodies))
+			}
+			for i, b := range bodies {
+				if b != bodies[0] {
+					t.Errorf("attempt %d sent body %q, expected %q", i+1, b, bodies[0])
+				}
+			}
+			if tc.method == "POST" && bodies[0] == "" {
+				t.Errorf("expected the request body to be sent")
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package provider
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+)
+
+// maxIdleConnsPerHost is sized for Terraform's default parallelism of 10
+// plus polling, all talking to the same API host.
+const maxIdleConnsPerHost = 16
+
+type TransportConfig struct {
+	HTTPProxy          string
+	CACertFile         string
+	InsecureSkipVerify bool
+}
+
+// newHTTPTransport builds the pooled transport shared by every client of a
+// provider instance.
+func newHTTPTransport(c TransportConfig) (*http.Transport, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConnsPerHost = maxIdleConnsPerHost
+
+	if c.HTTPProxy != "" {
+		proxyURL, err := url.Parse(c.HTTPProxy)
+		if err != nil {
+			return nil, fmt.Errorf("Error parsing http_proxy %q: %s", c.HTTPProxy, err)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	if c.CACertFile == "" && !c.InsecureSkipVerify {
+		return transport, nil
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+	}
+
+	if c.CACertFile != "" {
+		pem, err := os.ReadFile(c.CACertFile)
+		if err != nil {
+			return nil, fmt.Errorf("Error reading ca_cert_file: %s", err)
+		}
+
+		rootCAs, err := x509.SystemCertPool()
+		if err != nil || rootCAs == nil {
+			rootCAs = x509.NewCertPool()
+		}
+		if !rootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("Error reading ca_cert_file %s: no PEM certificates found", c.CACertFile)
+		}
+		tlsConfig.RootCAs = rootCAs
+	}
+
+	if c.InsecureSkipVerify {
+		log.Printf("[WARN] paperspace provider TLS certificate verification is disabled")
+		tlsConfig.InsecureSkipVerify = true
+	}
+
+	transport.TLSClientConfig = tlsConfig
+
+	return transport, nil
+}
@@ -0,0 +1,89 @@
+package provider
+
+import (
+	"encoding/pem"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNewHTTPTransport_caCertFile(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	caFile := filepath.Join(dir, "ca.pem")
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(caFile, caPEM, 0600); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	emptyFile := filepath.Join(dir, "empty.pem")
+	if err := os.WriteFile(emptyFile, []byte("not a certificate\n"), 0600); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	cases := []struct {
+		name   string
+		file   string
+		errMsg string
+	}{
+		{name: "missing file", file: filepath.Join(dir, "missing.pem"), errMsg: "Error reading ca_cert_file"},
+		{name: "no certificates", file: emptyFile, errMsg: "no PEM certificates found"},
+		{name: "valid bundle", file: caFile},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			transport, err := newHTTPTransport(TransportConfig{CACertFile: tc.file})
+			if tc.errMsg != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.errMsg) {
+					t.Fatalf("expected an error containing %q, got %v", tc.errMsg, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("err: %s", err)
+			}
+
+			resp, err := (&http.Client{Transport: transport}).Get(server.URL)
+			if err != nil {
+				t.Fatalf("expected the server certificate to be trusted, got %s", err)
+			}
+			resp.Body.Close()
+		})
+	}
+}
+
+func TestNewHTTPTransport_proxy(t *testing.T) {
+	var proxied string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = r.URL.String()
+		io.WriteString(w, "{}")
+	}))
+	defer proxy.Close()
+
+	transport, err := newHTTPTransport(TransportConfig{HTTPProxy: proxy.URL})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	resp, err := (&http.Client{Transport: transport}).Get("http://api.paperspace.invalid/machines/getMachines")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	resp.Body.Close()
+
+	if proxied != "http://api.paperspace.invalid/machines/getMachines" {
+		t.Errorf("expected the request to go through the proxy, proxy saw %q", proxied)
+	}
+
+	if _, err := newHTTPTransport(TransportConfig{HTTPProxy: "http://[::1"}); err == nil || !strings.Contains(err.Error(), "Error parsing http_proxy") {
+		t.Errorf("expected an invalid proxy URL to be rejected, got %v", err)
+	}
+}
@@ -0,0 +1,89 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// upgradeStateJSON upgrades state without a prior schema: fix rewrites the
+// attributes of the saved JSON, which is then read with the current schema.
+// Attributes the current schema no longer has are dropped.
+func upgradeStateJSON(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse, fix func(attributes map[string]interface{}) error) {
+	if req.RawState == nil {
+		resp.Diagnostics.AddError("Error upgrading paperspace state", "no saved state to upgrade")
+		return
+	}
+
+	var attributes map[string]interface{}
+	if err := json.Unmarshal(req.RawState.JSON, &attributes); err != nil {
+		resp.Diagnostics.AddError("Error upgrading paperspace state", err.Error())
+		return
+	}
+
+	if err := fix(attributes); err != nil {
+		resp.Diagnostics.AddError("Error upgrading paperspace state", err.Error())
+		return
+	}
+
+	data, err := json.Marshal(attributes)
+	if err != nil {
+		resp.Diagnostics.AddError("Error upgrading paperspace state", err.Error())
+		return
+	}
+
+	rawState := tfprotov6.RawState{JSON: data}
+	value, err := rawState.UnmarshalWithOpts(resp.State.Schema.Type().TerraformType(ctx), tfprotov6.UnmarshalOpts{
+		ValueFromJSONOpts: tftypes.ValueFromJSONOpts{IgnoreUndefinedAttributes: true},
+	})
+	if err != nil {
+		resp.Diagnostics.AddError("Error upgrading paperspace state", err.Error())
+		return
+	}
+
+	resp.State.Raw = value
+}
+
+// stringToInt64 converts an attribute the SDK based releases stored as a
+// string: empty strings become null and numeric strings numbers.
+func stringToInt64(attributes map[string]interface{}, name string) error {
+	s, ok := attributes[name].(string)
+	if !ok {
+		return nil
+	}
+
+	if s == "" {
+		attributes[name] = nil
+		return nil
+	}
+
+	v, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	attributes[name] = v
+
+	return nil
+}
+
+// zeroToNull nulls optional attributes the SDK based releases stored as their
+// zero value when unset: "" for strings and false for bools.
+func zeroToNull(attributes map[string]interface{}, names ...string) {
+	for _, name := range names {
+		switch v := attributes[name].(type) {
+		case string:
+			if v == "" {
+				attributes[name] = nil
+			}
+		case bool:
+			if !v {
+				attributes[name] = nil
+			}
+		}
+	}
+}